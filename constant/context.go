@@ -6,4 +6,10 @@ type ContextKey string
 const (
 	// TraceIDKey context 中 traceID 的 key
 	TraceIDKey ContextKey = "traceID"
+
+	// SampleDecisionKey context 中采样决策的 key，值为 bool，true 表示保留
+	SampleDecisionKey ContextKey = "sampleDecision"
+
+	// RequestPoolKey context 中请求级 bytes 对象池的 key，值为 *pool.RequestPool
+	RequestPoolKey ContextKey = "requestPool"
 )
@@ -4,4 +4,15 @@ go 1.24.4
 
 require golang.org/x/sync v0.19.0
 
-require gorm.io/gorm v1.31.1
+require (
+	github.com/alicebob/miniredis/v2 v2.37.0
+	github.com/redis/go-redis/v9 v9.17.2
+	golang.org/x/text v0.32.0
+	gorm.io/gorm v1.31.1
+)
+
+require (
+	github.com/cespare/xxhash/v2 v2.3.0 // indirect
+	github.com/dgryski/go-rendezvous v0.0.0-20200823014737-9f7001d12a5f // indirect
+	github.com/yuin/gopher-lua v1.1.1 // indirect
+)
@@ -1,8 +1,9 @@
 package gtask
 
 import (
-	"fmt"
+	"context"
 	"sync"
+	"time"
 )
 
 // Group 表示一个并发任务组
@@ -11,45 +12,115 @@ type Group struct {
 	AllowSomeFail bool // 是否允许部分失败
 
 	wg           sync.WaitGroup // 用于等待所有任务完成
-	semaphore    chan struct{}  // 用于控制并发数的信号量
 	mu           sync.Mutex     // 互斥锁，保护共享状态
+	cond         *sync.Cond     // 并发数达到上限时等待，基于 mu
+	limit        int            // 当前生效的并发上限，0 表示不限制
+	running      int            // 正在执行的任务数
 	errors       []error        // 收集所有错误
 	successCount int            // 成功任务计数
 	totalTasks   int            // 总任务数
+	outcomes     []TaskOutcome  // 按提交顺序记录每个任务的执行结果
 	once         sync.Once      // 用于一次性初始化资源
 }
 
+// TaskOutcome 单个任务的执行结果
+type TaskOutcome struct {
+	Index    int           // 任务的提交序号，从 0 开始，只统计实际被调度的任务
+	Err      error         // 任务返回的错误，panic 时为 *PanicError
+	Panicked bool          // 任务是否 panic
+	Duration time.Duration // 任务执行耗时
+}
+
 // Go 添加一个任务到任务组中
 func (g *Group) Go(task func() error) {
-	// 一次性初始化资源
-	g.once.Do(func() {
-		g.errors = make([]error, 0)
-		// 初始化信号量通道
-		if g.Concurrent > 0 {
-			g.semaphore = make(chan struct{}, g.Concurrent)
-		}
-	})
+	g.init()
 
 	// 如果不允许部分失败，检查是否已经有失败
 	if !g.AllowSomeFail && g.getHasFailed() {
 		return
 	}
 
-	g.addTotalTasks()
+	index := g.addTotalTasks()
 	g.wg.Add(1)
 
-	// 不做并发控制
-	if g.Concurrent == 0 {
-		go g.runTask(task)
-		return
+	// 并发数达到上限时阻塞等待
+	g.acquire()
+	go func() {
+		defer g.release()
+		g.runTask(index, task)
+	}()
+}
+
+// TryGo 尝试添加一个任务，并发数已达上限时不等待，直接返回 false 且不调度该任务
+// 只有任务被调度时才计入总任务数
+func (g *Group) TryGo(task func() error) bool {
+	g.init()
+
+	// 如果不允许部分失败，检查是否已经有失败
+	if !g.AllowSomeFail && g.getHasFailed() {
+		return false
+	}
+
+	if !g.tryAcquire() {
+		return false
 	}
 
-	// 使用信号量控制并发数
-	g.semaphore <- struct{}{}
+	index := g.addTotalTasks()
+	g.wg.Add(1)
 	go func() {
-		defer func() { <-g.semaphore }()
-		g.runTask(task)
+		defer g.release()
+		g.runTask(index, task)
 	}()
+	return true
+}
+
+// SetLimit 动态调整任务组的并发上限，n <= 0 表示不限制
+// 调小上限不会中断正在执行的任务，只会阻止新任务超出新的上限
+func (g *Group) SetLimit(n int) {
+	g.init()
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.limit = max(n, 0)
+	g.cond.Broadcast()
+}
+
+// init 一次性初始化资源
+func (g *Group) init() {
+	g.once.Do(func() {
+		g.errors = make([]error, 0)
+		g.cond = sync.NewCond(&g.mu)
+		g.limit = max(g.Concurrent, 0)
+	})
+}
+
+// acquire 占用一个并发名额，达到上限时阻塞
+func (g *Group) acquire() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for g.limit > 0 && g.running >= g.limit {
+		g.cond.Wait()
+	}
+	g.running++
+}
+
+// tryAcquire 尝试占用一个并发名额，达到上限时返回 false
+func (g *Group) tryAcquire() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.limit > 0 && g.running >= g.limit {
+		return false
+	}
+	g.running++
+	return true
+}
+
+// release 释放一个并发名额
+func (g *Group) release() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.running--
+	g.cond.Broadcast()
 }
 
 // Wait 等待所有任务完成，返回是否全部成功和错误信息
@@ -69,18 +140,41 @@ func (g *Group) Wait() (int, error) {
 	return successCount, g.joinErrors()
 }
 
-// addError 添加错误到错误列表
-func (g *Group) addError(err error) {
+// WaitContext 与 Wait 相同，但 ctx 结束时提前返回目前为止的成功数和 ctx.Err()
+// 提前返回不会中断已调度的任务，它们会在后台继续执行完毕，之后仍可调用 Wait 获取最终结果
+func (g *Group) WaitContext(ctx context.Context) (int, error) {
+	done := make(chan struct{})
+	go func() {
+		g.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return g.Wait()
+	case <-ctx.Done():
+		successCount, _, _ := g.getStats()
+		return successCount, ctx.Err()
+	}
+}
+
+// WaitDetailed 等待所有任务完成，按提交顺序返回每个任务的执行结果
+func (g *Group) WaitDetailed() []TaskOutcome {
+	g.wg.Wait()
+
 	g.mu.Lock()
 	defer g.mu.Unlock()
-	g.errors = append(g.errors, err)
+	return append([]TaskOutcome(nil), g.outcomes...)
 }
 
-// addTotalTasks 增加总任务数
-func (g *Group) addTotalTasks() {
+// addTotalTasks 增加总任务数，返回任务的提交序号
+func (g *Group) addTotalTasks() int {
 	g.mu.Lock()
 	defer g.mu.Unlock()
+	index := g.totalTasks
 	g.totalTasks++
+	g.outcomes = append(g.outcomes, TaskOutcome{Index: index})
+	return index
 }
 
 // hasFailed 检查是否已经有任务失败
@@ -90,46 +184,44 @@ func (g *Group) getHasFailed() bool {
 	return len(g.errors) > 0
 }
 
-// addSuccessCount 增加成功计数
-func (g *Group) addSuccessCount() {
-	g.mu.Lock()
-	defer g.mu.Unlock()
-	g.successCount++
-}
-
 // runTask 执行单个任务，包含 recover 机制
-func (g *Group) runTask(task func() error) {
+func (g *Group) runTask(index int, task func() error) {
 	defer g.wg.Done()
 
+	begin := time.Now()
+	outcome := TaskOutcome{Index: index}
 	defer func() {
 		if r := recover(); r != nil {
-			g.addError(fmt.Errorf("task panic: %v", r))
+			outcome.Err = newPanicError(r)
+			outcome.Panicked = true
 		}
+		outcome.Duration = time.Since(begin)
+		g.finishTask(outcome)
 	}()
 
-	err := task()
-	if err != nil {
-		g.addError(err)
+	outcome.Err = task()
+}
+
+// finishTask 记录任务的执行结果
+func (g *Group) finishTask(outcome TaskOutcome) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.outcomes[outcome.Index] = outcome
+	if outcome.Err != nil {
+		g.errors = append(g.errors, outcome.Err)
 		return
 	}
-
-	g.addSuccessCount()
+	g.successCount++
 }
 
-// joinErrors 将多个错误拼接成一个错误
+// joinErrors 将多个错误聚合为一个 MultiError，保留原始错误以便 errors.Is / errors.As
 func (g *Group) joinErrors() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
 	if len(g.errors) == 0 {
 		return nil
 	}
-
-	var errMsg string
-	for _, err := range g.errors {
-		if errMsg != "" {
-			errMsg += "; "
-		}
-		errMsg += err.Error()
-	}
-	return fmt.Errorf("%s", errMsg)
+	return &MultiError{Errs: append([]error(nil), g.errors...)}
 }
 
 // getStats 获取统计信息
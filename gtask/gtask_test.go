@@ -1,7 +1,11 @@
 package gtask
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"runtime"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -258,3 +262,235 @@ func findSubstring(s, substr string) bool {
 	}
 	return false
 }
+
+func TestGroupSetLimit(t *testing.T) {
+	g := &Group{Concurrent: 2}
+
+	var (
+		mu      sync.Mutex
+		current int
+		peak    int
+	)
+	task := func(d time.Duration) func() error {
+		return func() error {
+			mu.Lock()
+			current++
+			peak = max(peak, current)
+			mu.Unlock()
+
+			time.Sleep(d)
+
+			mu.Lock()
+			current--
+			mu.Unlock()
+			return nil
+		}
+	}
+
+	for i := 0; i < 4; i++ {
+		g.Go(task(20 * time.Millisecond))
+	}
+	mu.Lock()
+	before := peak
+	mu.Unlock()
+	if before > 2 {
+		t.Errorf("调整前并发数应不超过2，实际为%d", before)
+	}
+
+	// 运行中提升并发上限，后续任务应能达到更高的并发
+	g.SetLimit(5)
+	for i := 0; i < 10; i++ {
+		g.Go(task(50 * time.Millisecond))
+	}
+	successCount, err := g.Wait()
+	if err != nil || successCount != 14 {
+		t.Fatalf("期望14个任务全部成功，得到%d, %v", successCount, err)
+	}
+	if peak != 5 {
+		t.Errorf("调整后并发峰值应为5，实际为%d", peak)
+	}
+}
+
+func panicTask() error {
+	var m map[string]int
+	m["boom"] = 1
+	return nil
+}
+
+func TestGroupPanicError(t *testing.T) {
+	g := &Group{AllowSomeFail: true}
+	g.Go(panicTask)
+
+	_, err := g.Wait()
+	if err == nil {
+		t.Fatal("期望返回panic错误")
+	}
+	if !strings.Contains(err.Error(), "gtask.panicTask") {
+		t.Errorf("错误信息应包含panic的函数名，实际为: %v", err)
+	}
+	if strings.Contains(err.Error(), "\n") {
+		t.Errorf("错误信息应保持单行可读，实际为: %q", err)
+	}
+
+	var pe *PanicError
+	if !errors.As(g.errors[0], &pe) {
+		t.Fatalf("期望记录PanicError，实际为%T", g.errors[0])
+	}
+	if !strings.Contains(string(pe.Stack), "gtask.panicTask") {
+		t.Errorf("调用栈应包含panic的函数，实际为: %s", pe.Stack)
+	}
+	var re runtime.Error
+	if !errors.As(pe, &re) {
+		t.Errorf("runtime panic 应可通过 errors.As 取出 runtime.Error")
+	}
+}
+
+func TestGroupTryGo(t *testing.T) {
+	g := &Group{Concurrent: 1}
+
+	release := make(chan struct{})
+	if !g.TryGo(func() error {
+		<-release
+		return nil
+	}) {
+		t.Fatal("并发未满时TryGo应返回true")
+	}
+
+	for i := 0; i < 3; i++ {
+		if g.TryGo(func() error { return nil }) {
+			t.Fatal("并发已满时TryGo应返回false")
+		}
+	}
+
+	close(release)
+	deadline := time.Now().Add(time.Second)
+	for !g.TryGo(func() error { return nil }) {
+		if time.Now().After(deadline) {
+			t.Fatal("名额释放后TryGo应返回true")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	successCount, err := g.Wait()
+	if err != nil || successCount != 2 {
+		t.Errorf("期望2个任务成功且无错误，得到%d, %v", successCount, err)
+	}
+	if _, total, _ := g.getStats(); total != 2 {
+		t.Errorf("未调度的任务不应计入总数，总任务数为%d", total)
+	}
+}
+
+func TestGroupWaitUnwrap(t *testing.T) {
+	errNotFound := errors.New("not found")
+	g := &Group{AllowSomeFail: true}
+	g.Go(func() error { return nil })
+	g.Go(func() error { return fmt.Errorf("query user: %w", errNotFound) })
+	g.Go(func() error { panic("boom") })
+
+	_, err := g.Wait()
+	if !errors.Is(err, errNotFound) {
+		t.Errorf("errors.Is 应能匹配任务返回的错误，实际为: %v", err)
+	}
+	var pe *PanicError
+	if !errors.As(err, &pe) || pe.Value != "boom" {
+		t.Errorf("errors.As 应能取出 PanicError，实际为: %v", err)
+	}
+	var me *MultiError
+	if !errors.As(err, &me) || len(me.Errs) != 2 {
+		t.Errorf("期望返回包含2个错误的 MultiError，实际为: %#v", err)
+	}
+	if !strings.Contains(err.Error(), "; ") {
+		t.Errorf("错误信息应以 \"; \" 拼接，实际为: %q", err)
+	}
+}
+
+func TestGroupWaitDetailed(t *testing.T) {
+	errFailed := errors.New("failed")
+	g := &Group{AllowSomeFail: true}
+	g.Go(func() error {
+		time.Sleep(20 * time.Millisecond)
+		return nil
+	})
+	g.Go(func() error { return errFailed })
+	g.Go(func() error { panic("boom") })
+	g.Go(func() error { return nil })
+
+	outcomes := g.WaitDetailed()
+	if len(outcomes) != 4 {
+		t.Fatalf("期望4个任务结果，实际为%d", len(outcomes))
+	}
+	for i, o := range outcomes {
+		if o.Index != i {
+			t.Errorf("outcomes[%d].Index = %d, want %d", i, o.Index, i)
+		}
+	}
+
+	if o := outcomes[0]; o.Err != nil || o.Panicked || o.Duration < 20*time.Millisecond {
+		t.Errorf("outcomes[0] = %+v, want success with duration >= 20ms", o)
+	}
+	if o := outcomes[1]; !errors.Is(o.Err, errFailed) || o.Panicked {
+		t.Errorf("outcomes[1] = %+v, want errFailed", o)
+	}
+	var pe *PanicError
+	if o := outcomes[2]; !o.Panicked || !errors.As(o.Err, &pe) || pe.Value != "boom" {
+		t.Errorf("outcomes[2] = %+v, want panic boom", o)
+	}
+	if o := outcomes[3]; o.Err != nil || o.Panicked {
+		t.Errorf("outcomes[3] = %+v, want success", o)
+	}
+
+	// WaitDetailed 与 Wait 的统计保持一致
+	successCount, err := g.Wait()
+	if successCount != 2 || err == nil {
+		t.Errorf("Wait() = (%d, %v), want (2, error)", successCount, err)
+	}
+}
+
+func TestGroupWaitContext(t *testing.T) {
+	t.Run("ctx 取消时提前返回", func(t *testing.T) {
+		g := &Group{}
+		release := make(chan struct{})
+		g.Go(func() error { return nil })
+		for i := 0; i < 3; i++ {
+			g.Go(func() error {
+				<-release
+				return nil
+			})
+		}
+		// 等待快速任务完成，保证部分成功数稳定
+		time.Sleep(20 * time.Millisecond)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		begin := time.Now()
+		successCount, err := g.WaitContext(ctx)
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("WaitContext() error = %v, want context.DeadlineExceeded", err)
+		}
+		if successCount != 1 {
+			t.Errorf("WaitContext() successCount = %d, want 1", successCount)
+		}
+		if elapsed := time.Since(begin); elapsed > time.Second {
+			t.Errorf("WaitContext() returned after %v, want early return", elapsed)
+		}
+
+		// 已调度的任务在后台继续执行完毕
+		close(release)
+		successCount, err = g.Wait()
+		if successCount != 4 || err != nil {
+			t.Errorf("Wait() = (%d, %v), want (4, nil)", successCount, err)
+		}
+	})
+
+	t.Run("任务先完成", func(t *testing.T) {
+		g := &Group{AllowSomeFail: true}
+		errFailed := errors.New("failed")
+		g.Go(func() error { return nil })
+		g.Go(func() error { return errFailed })
+
+		successCount, err := g.WaitContext(context.Background())
+		if successCount != 1 || !errors.Is(err, errFailed) {
+			t.Errorf("WaitContext() = (%d, %v), want (1, errFailed)", successCount, err)
+		}
+	})
+}
@@ -0,0 +1,25 @@
+package gtask
+
+import "strings"
+
+// MultiError 任务组中多个任务返回的错误
+// Error() 以 "; " 拼接各错误信息，Unwrap 返回原始错误，可通过 errors.Is / errors.As 检查单个错误
+type MultiError struct {
+	Errs []error
+}
+
+func (e *MultiError) Error() string {
+	var b strings.Builder
+	for i, err := range e.Errs {
+		if i > 0 {
+			b.WriteString("; ")
+		}
+		b.WriteString(err.Error())
+	}
+	return b.String()
+}
+
+// Unwrap 返回所有原始错误
+func (e *MultiError) Unwrap() []error {
+	return e.Errs
+}
@@ -0,0 +1,61 @@
+package gtask
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"strings"
+)
+
+// PanicError 任务 panic 时记录的错误，包含 panic 的值和发生时的调用栈
+type PanicError struct {
+	Value    any    // recover 得到的值
+	Stack    []byte // panic 发生时的调用栈
+	Function string // 发生 panic 的函数名
+}
+
+// newPanicError 根据 recover 的值创建 PanicError，需在 defer 的 recover 函数中直接调用
+func newPanicError(v any) *PanicError {
+	return &PanicError{
+		Value:    v,
+		Stack:    debug.Stack(),
+		Function: panicFunction(),
+	}
+}
+
+// Error 只包含 panic 的值和函数名，保证多个错误拼接后仍然可读，完整调用栈见 Stack
+func (e *PanicError) Error() string {
+	if e.Function == "" {
+		return fmt.Sprintf("task panic: %v", e.Value)
+	}
+	return fmt.Sprintf("task panic: %v (at %s)", e.Value, e.Function)
+}
+
+// Unwrap panic 的值是 error 时返回该 error
+func (e *PanicError) Unwrap() error {
+	if err, ok := e.Value.(error); ok {
+		return err
+	}
+	return nil
+}
+
+// panicFunction 返回触发 panic 的函数名
+// 调用栈中 runtime.gopanic 等 runtime 帧之后的第一个非 runtime 帧即为发生 panic 的位置
+func panicFunction() string {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(3, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+
+	inRuntime := false
+	for {
+		frame, more := frames.Next()
+		isRuntime := strings.HasPrefix(frame.Function, "runtime.")
+		if inRuntime && !isRuntime {
+			return frame.Function
+		}
+		inRuntime = inRuntime || isRuntime
+		if !more {
+			return ""
+		}
+	}
+}
@@ -0,0 +1,200 @@
+package gtask
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	// ErrQueueFull 任务队列已满，OverflowError 策略下 Submit 返回该错误
+	ErrQueueFull = errors.New("gtask: worker pool queue is full")
+	// ErrPoolClosed 协程池已关闭
+	ErrPoolClosed = errors.New("gtask: worker pool is closed")
+)
+
+// OverflowPolicy 任务队列已满时 Submit 的处理策略
+type OverflowPolicy int
+
+const (
+	// OverflowBlock 阻塞等待队列有空位，默认策略
+	OverflowBlock OverflowPolicy = iota
+	// OverflowDrop 直接丢弃任务，计入 DroppedCount
+	OverflowDrop
+	// OverflowError 丢弃任务并返回 ErrQueueFull，计入 DroppedCount
+	OverflowError
+)
+
+// WorkerPoolOption 协程池配置
+type WorkerPoolOption struct {
+	Workers   int            // 工作协程数，<= 0 时为 1
+	QueueSize int            // 任务队列长度，< 0 时为 0
+	Policy    OverflowPolicy // 队列已满时的处理策略
+
+	// PanicHandler 任务 panic 时调用，在执行该任务的工作协程中同步调用，为 nil 时只计入 PanicCount
+	// PanicHandler 自身 panic 会被忽略，不会导致工作协程退出
+	PanicHandler func(err *PanicError)
+}
+
+// WorkerPool 固定数量工作协程的协程池
+type WorkerPool struct {
+	policy       OverflowPolicy
+	tasks        chan func()
+	dropped      atomic.Int64
+	panics       atomic.Int64
+	panicHandler func(err *PanicError)
+
+	// 向 tasks 发送时不持有 mu，mu 只保护 closed 与 senders 的登记，
+	// 阻塞在队列已满上的 Submit 不会卡住 Shutdown 和其他 Submit
+	mu      sync.RWMutex
+	closed  bool
+	senders sync.WaitGroup // 正在向 tasks 发送的 Submit，全部结束后才关闭 tasks
+	closing chan struct{}  // Shutdown 时关闭，唤醒阻塞在发送上的 Submit
+	wg      sync.WaitGroup // 等待工作协程退出
+
+	pendingMu   sync.Mutex
+	pendingCond *sync.Cond // pending 归零时广播，供 Wait 使用
+	pending     int        // 已提交但尚未执行完的任务数
+}
+
+// NewWorkerPool 创建协程池并启动工作协程，opt 为 nil 时使用默认配置
+func NewWorkerPool(opt *WorkerPoolOption) *WorkerPool {
+	if opt == nil {
+		opt = &WorkerPoolOption{}
+	}
+	workers := max(opt.Workers, 1)
+	queueSize := max(opt.QueueSize, 0)
+
+	p := &WorkerPool{
+		policy:       opt.Policy,
+		tasks:        make(chan func(), queueSize),
+		closing:      make(chan struct{}),
+		panicHandler: opt.PanicHandler,
+	}
+	p.pendingCond = sync.NewCond(&p.pendingMu)
+	p.wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go p.worker()
+	}
+	return p
+}
+
+// Submit 按协程池配置的策略提交任务
+func (p *WorkerPool) Submit(task func()) error {
+	return p.SubmitWithPolicy(task, p.policy)
+}
+
+// SubmitWithPolicy 按指定的策略提交任务，用于对单个任务覆盖协程池的默认策略
+// OverflowBlock 阻塞期间协程池被 Shutdown 时返回 ErrPoolClosed，任务不会执行
+func (p *WorkerPool) SubmitWithPolicy(task func(), policy OverflowPolicy) error {
+	p.mu.RLock()
+	if p.closed {
+		p.mu.RUnlock()
+		return ErrPoolClosed
+	}
+	p.senders.Add(1)
+	p.mu.RUnlock()
+	defer p.senders.Done()
+
+	p.addPending(1)
+	switch policy {
+	case OverflowDrop, OverflowError:
+		select {
+		case p.tasks <- task:
+			return nil
+		default:
+		}
+		p.addPending(-1)
+		p.dropped.Add(1)
+		if policy == OverflowError {
+			return ErrQueueFull
+		}
+		return nil
+	default:
+		select {
+		case p.tasks <- task:
+			return nil
+		case <-p.closing:
+			p.addPending(-1)
+			return ErrPoolClosed
+		}
+	}
+}
+
+// DroppedCount 返回因队列已满被丢弃的任务数
+func (p *WorkerPool) DroppedCount() int64 {
+	return p.dropped.Load()
+}
+
+// PanicCount 返回执行时 panic 的任务数
+func (p *WorkerPool) PanicCount() int64 {
+	return p.panics.Load()
+}
+
+// Wait 阻塞直到所有已提交的任务执行完毕，不会关闭协程池，之后仍可继续提交任务
+// 等待期间并发提交的任务也会被等待
+func (p *WorkerPool) Wait() {
+	p.pendingMu.Lock()
+	defer p.pendingMu.Unlock()
+	for p.pending > 0 {
+		p.pendingCond.Wait()
+	}
+}
+
+// Shutdown 停止接收新任务，并等待队列中已提交的任务执行完毕、所有工作协程退出
+// 阻塞在队列已满上的 Submit 返回 ErrPoolClosed
+// 可以重复调用，之后的调用同样等待工作协程退出，不会 panic
+func (p *WorkerPool) Shutdown() {
+	p.mu.Lock()
+	first := !p.closed
+	if first {
+		p.closed = true
+		close(p.closing)
+	}
+	p.mu.Unlock()
+
+	if first {
+		// 发送中的 Submit 看到 closing 后立即返回，之后不会再有发送，可以安全关闭 tasks
+		p.senders.Wait()
+		close(p.tasks)
+	}
+	p.wg.Wait()
+}
+
+// addPending 调整未执行完的任务数，归零时唤醒 Wait
+func (p *WorkerPool) addPending(delta int) {
+	p.pendingMu.Lock()
+	defer p.pendingMu.Unlock()
+	p.pending += delta
+	if p.pending == 0 {
+		p.pendingCond.Broadcast()
+	}
+}
+
+func (p *WorkerPool) worker() {
+	defer p.wg.Done()
+	for task := range p.tasks {
+		p.runTask(task)
+	}
+}
+
+// runTask 执行单个任务，panic 转换为 PanicError 交给 PanicHandler，不会导致工作协程退出
+func (p *WorkerPool) runTask(task func()) {
+	defer p.addPending(-1)
+	defer func() {
+		if r := recover(); r != nil {
+			p.panics.Add(1)
+			p.handlePanic(newPanicError(r))
+		}
+	}()
+	task()
+}
+
+// handlePanic 调用 PanicHandler，忽略其自身的 panic
+func (p *WorkerPool) handlePanic(err *PanicError) {
+	if p.panicHandler == nil {
+		return
+	}
+	defer func() { _ = recover() }()
+	p.panicHandler(err)
+}
@@ -0,0 +1,288 @@
+package gtask
+
+import (
+	"errors"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newBusyPool 创建一个工作协程被占用、队列已满的协程池，close(release) 后恢复执行
+func newBusyPool(t *testing.T, policy OverflowPolicy) (p *WorkerPool, release chan struct{}) {
+	t.Helper()
+	p = NewWorkerPool(&WorkerPoolOption{Workers: 1, QueueSize: 1, Policy: policy})
+	release = make(chan struct{})
+	started := make(chan struct{})
+
+	if err := p.Submit(func() {
+		close(started)
+		<-release
+	}); err != nil {
+		t.Fatalf("submit running task: %v", err)
+	}
+	<-started
+	if err := p.Submit(func() {}); err != nil {
+		t.Fatalf("submit queued task: %v", err)
+	}
+	return p, release
+}
+
+func TestWorkerPool_OverflowBlock(t *testing.T) {
+	p, release := newBusyPool(t, OverflowBlock)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- p.Submit(func() {})
+	}()
+
+	select {
+	case err := <-done:
+		t.Fatalf("Submit should block when queue is full, returned %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Submit returned %v, want nil", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Submit should return after queue drains")
+	}
+	p.Shutdown()
+
+	if got := p.DroppedCount(); got != 0 {
+		t.Errorf("DroppedCount = %d, want 0", got)
+	}
+}
+
+func TestWorkerPool_OverflowDrop(t *testing.T) {
+	p, release := newBusyPool(t, OverflowDrop)
+
+	executed := false
+	for i := 0; i < 3; i++ {
+		if err := p.Submit(func() { executed = true }); err != nil {
+			t.Errorf("Submit returned %v, want nil", err)
+		}
+	}
+
+	close(release)
+	p.Shutdown()
+
+	if executed {
+		t.Error("dropped task should not be executed")
+	}
+	if got := p.DroppedCount(); got != 3 {
+		t.Errorf("DroppedCount = %d, want 3", got)
+	}
+}
+
+func TestWorkerPool_OverflowError(t *testing.T) {
+	p, release := newBusyPool(t, OverflowError)
+
+	if err := p.Submit(func() {}); !errors.Is(err, ErrQueueFull) {
+		t.Errorf("Submit returned %v, want ErrQueueFull", err)
+	}
+	if got := p.DroppedCount(); got != 1 {
+		t.Errorf("DroppedCount = %d, want 1", got)
+	}
+
+	// 单个任务可以覆盖协程池的默认策略
+	if err := p.SubmitWithPolicy(func() {}, OverflowDrop); err != nil {
+		t.Errorf("SubmitWithPolicy(OverflowDrop) returned %v, want nil", err)
+	}
+	if got := p.DroppedCount(); got != 2 {
+		t.Errorf("DroppedCount = %d, want 2", got)
+	}
+
+	close(release)
+	p.Shutdown()
+
+	if err := p.Submit(func() {}); !errors.Is(err, ErrPoolClosed) {
+		t.Errorf("Submit after Shutdown returned %v, want ErrPoolClosed", err)
+	}
+}
+
+func TestWorkerPool_Wait(t *testing.T) {
+	p := NewWorkerPool(&WorkerPoolOption{Workers: 4, QueueSize: 16})
+	defer p.Shutdown()
+
+	var finished atomic.Int32
+	for i := 0; i < 20; i++ {
+		if err := p.Submit(func() {
+			time.Sleep(5 * time.Millisecond)
+			finished.Add(1)
+		}); err != nil {
+			t.Fatalf("Submit returned %v", err)
+		}
+	}
+	p.Wait()
+	if got := finished.Load(); got != 20 {
+		t.Errorf("finished = %d after Wait, want 20", got)
+	}
+
+	// Wait 不会关闭协程池，之后仍可提交任务
+	if err := p.Submit(func() { finished.Add(1) }); err != nil {
+		t.Fatalf("Submit after Wait returned %v", err)
+	}
+	p.Wait()
+	if got := finished.Load(); got != 21 {
+		t.Errorf("finished = %d, want 21", got)
+	}
+
+	// 被丢弃的任务不计入等待
+	busy, release := newBusyPool(t, OverflowDrop)
+	_ = busy.Submit(func() {})
+	close(release)
+	busy.Wait()
+	busy.Shutdown()
+}
+
+func TestWorkerPool_ShutdownNoLeak(t *testing.T) {
+	before := runtime.NumGoroutine()
+
+	p := NewWorkerPool(&WorkerPoolOption{Workers: 8, QueueSize: 8})
+	for i := 0; i < 32; i++ {
+		_ = p.Submit(func() { time.Sleep(time.Millisecond) })
+	}
+	p.Shutdown()
+	// 重复调用不会 panic 或阻塞
+	done := make(chan struct{})
+	go func() {
+		p.Shutdown()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("second Shutdown should return")
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if after := runtime.NumGoroutine(); after > before {
+		t.Errorf("goroutines after Shutdown = %d, before = %d, worker goroutines leaked", after, before)
+	}
+}
+
+func TestWorkerPool_ShutdownWithBlockedSubmit(t *testing.T) {
+	p, release := newBusyPool(t, OverflowBlock)
+
+	blocked := make(chan error, 1)
+	go func() {
+		blocked <- p.Submit(func() {})
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	shutdown := make(chan struct{})
+	go func() {
+		p.Shutdown()
+		close(shutdown)
+	}()
+
+	// 阻塞的 Submit 被 Shutdown 唤醒，而不是让 Shutdown 等待它
+	select {
+	case err := <-blocked:
+		if !errors.Is(err, ErrPoolClosed) {
+			t.Errorf("blocked Submit returned %v, want ErrPoolClosed", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("blocked Submit should return after Shutdown")
+	}
+
+	// Shutdown 等待运行中的任务期间，其他 Submit 立即返回
+	done := make(chan error, 1)
+	go func() {
+		done <- p.SubmitWithPolicy(func() {}, OverflowError)
+	}()
+	select {
+	case err := <-done:
+		if !errors.Is(err, ErrPoolClosed) {
+			t.Errorf("Submit during Shutdown returned %v, want ErrPoolClosed", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Submit during Shutdown should not block")
+	}
+
+	close(release)
+	select {
+	case <-shutdown:
+	case <-time.After(time.Second):
+		t.Fatal("Shutdown should return after running tasks finish")
+	}
+}
+
+func TestWorkerPool_TaskSubmitsWhileQueueFull(t *testing.T) {
+	p := NewWorkerPool(&WorkerPoolOption{Workers: 1, QueueSize: 1})
+	inner := make(chan error, 1)
+	// 任务在队列已满时阻塞提交，Shutdown 仍能结束并释放它
+	_ = p.Submit(func() {
+		_ = p.Submit(func() {})
+		inner <- p.Submit(func() {})
+	})
+
+	done := make(chan struct{})
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		p.Shutdown()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Shutdown deadlocked on a task blocked in Submit")
+	}
+	if err := <-inner; !errors.Is(err, ErrPoolClosed) {
+		t.Errorf("nested Submit returned %v, want ErrPoolClosed", err)
+	}
+}
+
+func TestWorkerPool_PanicHandler(t *testing.T) {
+	var got atomic.Pointer[PanicError]
+	p := NewWorkerPool(&WorkerPoolOption{
+		Workers: 1,
+		PanicHandler: func(err *PanicError) {
+			got.Store(err)
+			panic("handler panic") // 处理器自身 panic 不影响工作协程
+		},
+	})
+	defer p.Shutdown()
+
+	_ = p.Submit(func() { panic("boom") })
+	p.Wait()
+
+	err := got.Load()
+	if err == nil {
+		t.Fatal("PanicHandler should receive the task panic")
+	}
+	if err.Value != "boom" || len(err.Stack) == 0 {
+		t.Errorf("PanicError = %+v, want Value boom with stack", err)
+	}
+	if n := p.PanicCount(); n != 1 {
+		t.Errorf("PanicCount() = %d, want 1", n)
+	}
+
+	// panic 后工作协程仍然可以执行任务
+	var ran atomic.Bool
+	_ = p.Submit(func() { ran.Store(true) })
+	p.Wait()
+	if !ran.Load() {
+		t.Error("worker should keep running after a task panic")
+	}
+}
+
+func TestWorkerPool_PanicWithoutHandler(t *testing.T) {
+	p := NewWorkerPool(&WorkerPoolOption{Workers: 1})
+	defer p.Shutdown()
+
+	_ = p.Submit(func() { panic("boom") })
+	_ = p.Submit(func() { panic("boom") })
+	p.Wait()
+	if n := p.PanicCount(); n != 2 {
+		t.Errorf("PanicCount() = %d, want 2", n)
+	}
+}
@@ -2,8 +2,10 @@ package logger
 
 import (
 	"errors"
+	"fmt"
 	"io"
 	"log/slog"
+	"time"
 )
 
 type Config struct {
@@ -28,6 +30,11 @@ type Config struct {
 	// 默认为0，不超时，若出现落盘慢的时候，调用写日志的地方会出现同步等待
 	WriterTimeout int `json:"writerTimeout" yaml:"writerTimeout"`
 
+	// 关闭 logger 时等待缓冲队列写完的最长时间，毫秒
+	// 默认为0，一直等待到写完；若>0，超时后丢弃未写完的日志，closeFunc 返回的错误中包含丢弃条数，
+	// 避免缓冲较大且落盘慢时阻塞进程退出（如容器收到 SIGTERM 后的宽限期有限）
+	CloseTimeout int `json:"closeTimeout" yaml:"closeTimeout"`
+
 	// 日志落盘刷新间隔，毫秒
 	// 若<=0，使用默认值1000
 	FlushDuration int `json:"flushDuration" yaml:"flushDuration"`
@@ -35,6 +42,28 @@ type Config struct {
 	// 日志等级
 	Level slog.Level `json:"level" yaml:"level"`
 
+	// 字符串形式的日志等级，如 debug、info、warn、error，不区分大小写
+	// 便于从 YAML/环境变量配置，不为空时覆盖 Level，无法识别时 Validate 返回错误
+	LevelString string `json:"levelString" yaml:"levelString"`
+
+	// 文件切分边界及文件名后缀所使用的时区，默认为 time.Local
+	// 如服务器使用 UTC，但希望按北京时间0点切分，可设置为 Asia/Shanghai
+	Location *time.Location `json:"-" yaml:"-"`
+
+	// 日志写入或落盘失败时的回调（如磁盘已满），可用于统计或告警
+	// 回调异步执行，请勿在回调中使用当前 logger 输出日志
+	OnWriteError func(error) `json:"-" yaml:"-"`
+
+	// 每次落盘刷新后调用 fsync，避免机器掉电时丢失最后的日志
+	// fsync 是同步的磁盘 IO，会降低写入吞吐，默认关闭，详见 writer.RotateOption.SyncOnFlush
+	SyncOnFlush bool `json:"syncOnFlush" yaml:"syncOnFlush"`
+
+	// 是否在每行日志中输出 version=，版本号见 SetVersion
+	AddVersion bool `json:"addVersion" yaml:"addVersion"`
+
+	// 每行日志都携带的默认属性，如 service=payments，输出在每条日志自身的属性之前
+	DefaultAttrs []slog.Attr `json:"-" yaml:"-"`
+
 	writer io.WriteCloser
 }
 
@@ -43,6 +72,11 @@ func (c *Config) Validate() error {
 	if c.FileName == "" {
 		return errors.New("FileName is required")
 	}
+	if c.LevelString != "" {
+		if _, err := parseLevel(c.LevelString); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -60,4 +94,22 @@ func (c *Config) SetDefaults() {
 	if c.FlushDuration <= 0 {
 		c.FlushDuration = 1000
 	}
+	if c.Location == nil {
+		c.Location = time.Local
+	}
+	if c.LevelString != "" {
+		if level, err := parseLevel(c.LevelString); err == nil {
+			c.Level = level
+		}
+	}
+}
+
+// parseLevel 解析字符串形式的日志等级，不区分大小写
+// 同 slog.Level 的文本格式，也支持 info+2 这类带偏移的写法
+func parseLevel(s string) (slog.Level, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(s)); err != nil {
+		return level, fmt.Errorf("invalid LevelString %q: %w", s, err)
+	}
+	return level, nil
 }
@@ -0,0 +1,46 @@
+package logger
+
+import (
+	"log/slog"
+	"testing"
+)
+
+func TestConfig_LevelString(t *testing.T) {
+	tests := []struct {
+		levelString string
+		want        slog.Level
+	}{
+		{levelString: "debug", want: slog.LevelDebug},
+		{levelString: "INFO", want: slog.LevelInfo},
+		{levelString: "Warn", want: slog.LevelWarn},
+		{levelString: "error", want: slog.LevelError},
+		{levelString: "info+2", want: slog.LevelInfo + 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.levelString, func(t *testing.T) {
+			conf := &Config{FileName: "app.log", Level: slog.LevelError, LevelString: tt.levelString}
+			if err := conf.Validate(); err != nil {
+				t.Fatalf("Validate() error = %v", err)
+			}
+			conf.SetDefaults()
+			if conf.Level != tt.want {
+				t.Errorf("Level = %v, want %v", conf.Level, tt.want)
+			}
+		})
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		conf := &Config{FileName: "app.log", LevelString: "verbose"}
+		if err := conf.Validate(); err == nil {
+			t.Error("Validate() should fail for unknown level")
+		}
+	})
+
+	t.Run("empty keeps Level", func(t *testing.T) {
+		conf := &Config{FileName: "app.log", Level: slog.LevelWarn}
+		conf.SetDefaults()
+		if conf.Level != slog.LevelWarn {
+			t.Errorf("Level = %v, want %v", conf.Level, slog.LevelWarn)
+		}
+	})
+}
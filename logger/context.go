@@ -0,0 +1,44 @@
+package logger
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/Twelveeee/golib/constant"
+	"github.com/Twelveeee/golib/logger/handler"
+)
+
+// WithTraceID 在 context 中设置 traceID，handler 会将其输出为 traceID=
+func WithTraceID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, constant.TraceIDKey, id)
+}
+
+// TraceIDFromContext 读取 context 中的 traceID，不存在或为空时 ok 为 false
+func TraceIDFromContext(ctx context.Context) (string, bool) {
+	return handler.TraceIDFromContext(ctx)
+}
+
+// WithSampleDecision 在 context 中设置本次请求的采样决策
+// 一般在请求入口（如中间件）设置一次，链路上所有的 SamplingHandler 都会使用该决策，
+// 从而对同一个请求做出一致的保留/丢弃选择
+func WithSampleDecision(ctx context.Context, keep bool) context.Context {
+	return context.WithValue(ctx, constant.SampleDecisionKey, keep)
+}
+
+// EnsureTraceID 确保 context 中存在 traceID
+// 已存在时直接返回；不存在时生成一个新的 traceID 写入 context，适合在请求入口的中间件中调用
+func EnsureTraceID(ctx context.Context) (context.Context, string) {
+	if id, ok := TraceIDFromContext(ctx); ok {
+		return ctx, id
+	}
+	id := newTraceID()
+	return WithTraceID(ctx, id), id
+}
+
+// newTraceID 生成 16 字节随机数的十六进制字符串
+func newTraceID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
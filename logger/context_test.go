@@ -0,0 +1,76 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/Twelveeee/golib/logger/handler"
+)
+
+func TestWithSampleDecision(t *testing.T) {
+	var fileOut, stdOut bytes.Buffer
+	l := slog.New(handler.NewMultiHandler(
+		handler.NewSamplingHandler(handler.NewDefaultHandler(&fileOut, slog.LevelInfo), 0.5),
+		handler.NewSamplingHandler(handler.NewDefaultHandler(&stdOut, slog.LevelInfo), 0.5),
+	))
+
+	const requests = 200
+	for i := 0; i < requests; i++ {
+		keep := i%2 == 0
+		ctx := WithSampleDecision(context.Background(), keep)
+		l.InfoContext(ctx, "request")
+	}
+
+	fileLines := strings.Count(fileOut.String(), "\n")
+	stdLines := strings.Count(stdOut.String(), "\n")
+	if fileLines != requests/2 || stdLines != requests/2 {
+		t.Fatalf("both sampling handlers should keep exactly the requests marked keep, got file=%d std=%d", fileLines, stdLines)
+	}
+	if fileOut.String() != stdOut.String() {
+		t.Fatal("sampling handlers made different decisions for the same requests")
+	}
+}
+
+func TestWithTraceID(t *testing.T) {
+	if _, ok := TraceIDFromContext(context.Background()); ok {
+		t.Error("empty context should not contain trace id")
+	}
+
+	ctx := WithTraceID(context.Background(), "abc123")
+	id, ok := TraceIDFromContext(ctx)
+	if !ok || id != "abc123" {
+		t.Errorf("TraceIDFromContext() = (%q, %v), want (\"abc123\", true)", id, ok)
+	}
+
+	var out bytes.Buffer
+	slog.New(handler.NewDefaultHandler(&out, slog.LevelInfo)).InfoContext(ctx, "hello")
+	if !strings.Contains(out.String(), " traceID=abc123 ") {
+		t.Errorf("output = %q, want traceID=abc123", out.String())
+	}
+}
+
+func TestEnsureTraceID(t *testing.T) {
+	t.Run("已存在时复用", func(t *testing.T) {
+		ctx := WithTraceID(context.Background(), "abc123")
+		got, id := EnsureTraceID(ctx)
+		if id != "abc123" || got != ctx {
+			t.Errorf("EnsureTraceID() = (%v, %q), want existing context and abc123", got, id)
+		}
+	})
+
+	t.Run("不存在时生成", func(t *testing.T) {
+		ctx, id := EnsureTraceID(context.Background())
+		if len(id) != 32 {
+			t.Errorf("generated trace id = %q, want 32 hex chars", id)
+		}
+		if got, ok := TraceIDFromContext(ctx); !ok || got != id {
+			t.Errorf("TraceIDFromContext() = (%q, %v), want (%q, true)", got, ok, id)
+		}
+		if _, other := EnsureTraceID(context.Background()); other == id {
+			t.Errorf("generated trace ids should be unique, got %q twice", id)
+		}
+	})
+}
@@ -5,8 +5,11 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
+	"unicode/utf8"
 
 	gormLogger "gorm.io/gorm/logger"
 )
@@ -17,8 +20,17 @@ type GormAdapter struct {
 	logLevel                  gormLogger.LogLevel
 	slowThreshold             time.Duration
 	ignoreRecordNotFoundError bool
+	nowFunc                   func() time.Time
+	isRetryable               func(error) bool
+	onSlowQuery               SlowQueryCallback
+	maxSQLLength              int
+	logArgs                   bool
+	pendingArgs               *gormPendingArgs
 }
 
+// SlowQueryCallback 慢查询回调，sql 为清理后的 SQL 语句
+type SlowQueryCallback func(ctx context.Context, sql string, elapsed time.Duration, rows int64)
+
 // GormAdapterOption 配置选项
 type GormAdapterOption func(*GormAdapter)
 
@@ -43,6 +55,51 @@ func WithIgnoreRecordNotFoundError(ignore bool) GormAdapterOption {
 	}
 }
 
+// WithGormNowFunc 设置日志时间的获取函数，默认为 time.Now
+// 回放历史 SQL 等场景可以通过它让日志使用事件的原始时间
+func WithGormNowFunc(fn func() time.Time) GormAdapterOption {
+	return func(a *GormAdapter) {
+		if fn != nil {
+			a.nowFunc = fn
+		}
+	}
+}
+
+// WithRetryableErrorClassifier 设置可重试错误的判断函数
+// 被判定为可重试的错误（如事务死锁、序列化失败）以 Warn 级别记录，并带上 retryable=true，
+// 避免应用层会自动重试的错误被当作 Error 告警；LogMode 低于 Warn 时不记录
+func WithRetryableErrorClassifier(fn func(error) bool) GormAdapterOption {
+	return func(a *GormAdapter) {
+		a.isRetryable = fn
+	}
+}
+
+// WithSlowQueryCallback 设置慢查询回调，可用于告警或上报监控指标
+// 所有超过慢查询阈值的 SQL（包括执行出错的）都会触发，不受日志级别影响，LogMode(Silent) 时同样调用；
+// 回调在记录日志之前、在 Trace 中同步执行，耗时操作需自行异步处理
+func WithSlowQueryCallback(fn SlowQueryCallback) GormAdapterOption {
+	return func(a *GormAdapter) {
+		a.onSlowQuery = fn
+	}
+}
+
+// WithMaxSQLLength 设置日志中 SQL 语句的最大长度（字符数），超出部分截断并追加 ...(truncated)
+// 用于避免批量插入等超长 SQL 占用大量日志存储，<= 0 表示不限制
+func WithMaxSQLLength(n int) GormAdapterOption {
+	return func(a *GormAdapter) {
+		a.maxSQLLength = n
+	}
+}
+
+// WithGormArgs 设置是否额外记录插值前的 SQL 模板（stmt）与绑定参数（args）
+// 开启后 GormAdapter 实现 gorm.ParamsFilter，由 GORM 在生成日志 SQL 时传入原始语句与参数，
+// 便于排查插值后的 SQL 难以还原的问题（如二进制参数、特殊字符转义）
+func WithGormArgs(enable bool) GormAdapterOption {
+	return func(a *GormAdapter) {
+		a.logArgs = enable
+	}
+}
+
 // NewGormAdapter 创建一个新的 GORM 日志适配器
 func NewGormAdapter(logger *slog.Logger, opts ...GormAdapterOption) gormLogger.Interface {
 	adapter := &GormAdapter{
@@ -50,6 +107,8 @@ func NewGormAdapter(logger *slog.Logger, opts ...GormAdapterOption) gormLogger.I
 		logLevel:                  gormLogger.Info,
 		slowThreshold:             200 * time.Millisecond,
 		ignoreRecordNotFoundError: false,
+		nowFunc:                   time.Now,
+		pendingArgs:               &gormPendingArgs{},
 	}
 
 	for _, opt := range opts {
@@ -89,45 +148,189 @@ func (a *GormAdapter) Error(ctx context.Context, msg string, data ...interface{}
 
 // Trace 实现 gorm logger.Interface，用于记录 SQL 执行信息
 func (a *GormAdapter) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
-	if a.logLevel <= gormLogger.Silent {
+	elapsed := time.Since(begin)
+	slow := a.slowThreshold != 0 && elapsed > a.slowThreshold
+	if a.logLevel <= gormLogger.Silent && (!slow || a.onSlowQuery == nil) {
 		return
 	}
 
-	elapsed := time.Since(begin)
+	if a.logArgs {
+		a.pendingArgs.begin(ctx)
+		defer a.pendingArgs.end(ctx)
+	}
 	sql, rows := fc()
 
-	// 清理 SQL 中的换行符和多余空格
-	sql = cleanSQL(sql)
+	// 取出 ParamsFilter 记录的原始语句与参数，并去掉 SQL 中的标记
+	sql, stmt, hasStmt := a.pendingArgs.take(ctx, sql)
+
+	// 清理 SQL 中的换行符和多余空格，并按配置截断
+	sql = cleanSQL(sql, a.maxSQLLength)
+
+	// 慢查询回调不受日志级别影响，日志关闭时监控指标照常上报
+	if slow && a.onSlowQuery != nil {
+		a.onSlowQuery(ctx, sql, elapsed, rows)
+	}
+	if a.logLevel <= gormLogger.Silent {
+		return
+	}
+
+	attrs := []slog.Attr{
+		slog.String("sql", sql),
+		slog.Int64("rows", rows),
+		slog.Duration("elapsed", elapsed),
+	}
+	if hasStmt {
+		attrs = append(attrs,
+			slog.String("stmt", cleanSQL(stmt.sql, a.maxSQLLength)),
+			slog.Any("args", stmt.args),
+		)
+	}
 
 	switch {
+	case err != nil && a.isRetryable != nil && a.isRetryable(err):
+		// 记录可重试的错误，日志级别低于 Warn 时不记录，而不是升级为 Error
+		if a.logLevel >= gormLogger.Warn {
+			a.logAttrsWithoutCaller(ctx, slog.LevelWarn, "gorm trace retryable error", append(attrs,
+				slog.String("error", err.Error()),
+				slog.Bool("retryable", true),
+			)...)
+		}
 	case err != nil && a.logLevel >= gormLogger.Error && (!errors.Is(err, gormLogger.ErrRecordNotFound) || !a.ignoreRecordNotFoundError):
 		// 记录错误
-		a.logAttrsWithoutCaller(ctx, slog.LevelError, "gorm trace error",
-			slog.String("sql", sql),
-			slog.Int64("rows", rows),
-			slog.Duration("elapsed", elapsed),
+		a.logAttrsWithoutCaller(ctx, slog.LevelError, "gorm trace error", append(attrs,
 			slog.String("error", err.Error()),
-		)
-	case elapsed > a.slowThreshold && a.slowThreshold != 0 && a.logLevel >= gormLogger.Warn:
+		)...)
+	case slow && a.logLevel >= gormLogger.Warn:
 		// 记录慢查询
-		a.logAttrsWithoutCaller(ctx, slog.LevelWarn, "gorm slow query",
-			slog.String("sql", sql),
-			slog.Int64("rows", rows),
-			slog.Duration("elapsed", elapsed),
+		a.logAttrsWithoutCaller(ctx, slog.LevelWarn, "gorm slow query", append(attrs,
 			slog.Duration("threshold", a.slowThreshold),
-		)
+		)...)
 	case a.logLevel >= gormLogger.Info:
 		// 记录普通查询
-		a.logAttrsWithoutCaller(ctx, slog.LevelInfo, "gorm trace",
-			slog.String("sql", sql),
-			slog.Int64("rows", rows),
-			slog.Duration("elapsed", elapsed),
-		)
+		a.logAttrsWithoutCaller(ctx, slog.LevelInfo, "gorm trace", attrs...)
 	}
 }
 
-// cleanSQL 清理 SQL 语句中的换行符和多余空格
-func cleanSQL(sql string) string {
+// ParamsFilter 实现 gorm.ParamsFilter，GORM 在 Trace 中生成日志 SQL 前调用
+// 开启 WithGormArgs 且处于本适配器的 Trace 中时，记录插值前的语句与参数，并在返回的 SQL 前加上标记，
+// Trace 据此取回并去掉标记；不在 Trace 中调用（如被其他 logger 包装）时原样返回，不加标记
+func (a *GormAdapter) ParamsFilter(ctx context.Context, sql string, params ...interface{}) (string, []interface{}) {
+	if !a.logArgs {
+		return sql, params
+	}
+	if marker, ok := a.pendingArgs.put(ctx, sql, params); ok {
+		return marker + sql, params
+	}
+	return sql, params
+}
+
+// gormStatement 插值前的 SQL 语句与绑定参数
+type gormStatement struct {
+	sql  string
+	args []interface{}
+}
+
+// gormPendingArgs 暂存 ParamsFilter 记录的语句，LogMode 派生出的 GormAdapter 共享同一份
+// GORM 只在 Trace 调用 fc 时同步调用 ParamsFilter，且两者使用同一个 context，
+// 因此暂存的语句按 context 归属于正在执行的 Trace，最后一个 Trace 结束时整体删除，不会残留；
+// 同一个 context 可能被多个协程并发使用，因此再以自增 ID 的标记关联 ParamsFilter 与 Trace
+type gormPendingArgs struct {
+	mu     sync.Mutex
+	seq    uint64
+	traces map[context.Context]*gormTraceArgs
+}
+
+// gormTraceArgs 同一个 context 上正在执行的 Trace 共享的暂存语句
+type gormTraceArgs struct {
+	refs  int
+	stmts map[uint64]gormStatement
+}
+
+const (
+	gormArgsMarkerPrefix = "/* golib-args:"
+	gormArgsMarkerSuffix = " */ "
+)
+
+// begin Trace 调用 fc 前登记，之后 ParamsFilter 才会暂存语句，需与 end 成对调用
+func (p *gormPendingArgs) begin(ctx context.Context) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.traces == nil {
+		p.traces = make(map[context.Context]*gormTraceArgs)
+	}
+	t := p.traces[ctx]
+	if t == nil {
+		t = &gormTraceArgs{stmts: make(map[uint64]gormStatement)}
+		p.traces[ctx] = t
+	}
+	t.refs++
+}
+
+// end Trace 结束时调用，该 context 上没有其他 Trace 时删除全部暂存语句
+func (p *gormPendingArgs) end(ctx context.Context) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if t := p.traces[ctx]; t != nil {
+		if t.refs--; t.refs == 0 {
+			delete(p.traces, ctx)
+		}
+	}
+}
+
+// put 暂存语句，返回需要加在 SQL 前的标记；ctx 上没有正在执行的 Trace 时返回 false
+func (p *gormPendingArgs) put(ctx context.Context, sql string, args []interface{}) (string, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	t := p.traces[ctx]
+	if t == nil {
+		return "", false
+	}
+	p.seq++
+	t.stmts[p.seq] = gormStatement{sql: sql, args: args}
+	return gormArgsMarkerPrefix + strconv.FormatUint(p.seq, 10) + gormArgsMarkerSuffix, true
+}
+
+// take 去掉 SQL 中所有的标记，并取出第一个标记对应的暂存语句
+// 标记不一定在开头（如方言改写了 SQL），因此在整个 SQL 中查找
+func (p *gormPendingArgs) take(ctx context.Context, sql string) (string, gormStatement, bool) {
+	var (
+		stmt  gormStatement
+		found bool
+	)
+	for {
+		i := strings.Index(sql, gormArgsMarkerPrefix)
+		if i < 0 {
+			return sql, stmt, found
+		}
+		idStr, rest, ok := strings.Cut(sql[i+len(gormArgsMarkerPrefix):], gormArgsMarkerSuffix)
+		if !ok {
+			return sql, stmt, found
+		}
+		sql = sql[:i] + rest
+		if id, err := strconv.ParseUint(idStr, 10, 64); err == nil && !found {
+			stmt, found = p.load(ctx, id)
+		}
+	}
+}
+
+// load 取出并删除暂存的语句
+func (p *gormPendingArgs) load(ctx context.Context, id uint64) (gormStatement, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	t := p.traces[ctx]
+	if t == nil {
+		return gormStatement{}, false
+	}
+	stmt, ok := t.stmts[id]
+	delete(t.stmts, id)
+	return stmt, ok
+}
+
+// sqlTruncatedSuffix SQL 被截断时追加的后缀
+const sqlTruncatedSuffix = "...(truncated)"
+
+// cleanSQL 清理 SQL 语句中的换行符和多余空格，maxLen > 0 时截断到 maxLen 个字符
+func cleanSQL(sql string, maxLen int) string {
 	// 替换所有换行符为空格
 	sql = strings.ReplaceAll(sql, "\n", " ")
 	sql = strings.ReplaceAll(sql, "\r", " ")
@@ -139,7 +342,20 @@ func cleanSQL(sql string) string {
 	}
 
 	// 去除首尾空格
-	return strings.TrimSpace(sql)
+	sql = strings.TrimSpace(sql)
+
+	if maxLen <= 0 || len(sql) <= maxLen || utf8.RuneCountInString(sql) <= maxLen {
+		return sql
+	}
+	// 截断在第 maxLen 个字符之后
+	runes := 0
+	for i := range sql {
+		if runes == maxLen {
+			return sql[:i] + sqlTruncatedSuffix
+		}
+		runes++
+	}
+	return sql
 }
 
 // logWithoutCaller 记录日志但不包含 caller 信息
@@ -147,7 +363,7 @@ func (a *GormAdapter) logWithoutCaller(ctx context.Context, level slog.Level, ms
 	if !a.logger.Enabled(ctx, level) {
 		return
 	}
-	r := slog.NewRecord(time.Now(), level, msg, 0)
+	r := slog.NewRecord(a.nowFunc(), level, msg, 0)
 	_ = a.logger.Handler().Handle(ctx, r)
 }
 
@@ -156,7 +372,7 @@ func (a *GormAdapter) logAttrsWithoutCaller(ctx context.Context, level slog.Leve
 	if !a.logger.Enabled(ctx, level) {
 		return
 	}
-	r := slog.NewRecord(time.Now(), level, msg, 0)
+	r := slog.NewRecord(a.nowFunc(), level, msg, 0)
 	r.AddAttrs(attrs...)
 	_ = a.logger.Handler().Handle(ctx, r)
 }
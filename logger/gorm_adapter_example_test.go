@@ -51,7 +51,7 @@ func ExampleNewGormAdapter_withContext() {
 		WriterTimeout: 3000,
 	}
 
-	slogger, closeFunc, err := logger.NewLogger(ctx, conf)
+	slogger, closeFunc, _, err := logger.NewLogger(ctx, conf)
 	if err != nil {
 		panic(err)
 	}
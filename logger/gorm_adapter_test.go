@@ -0,0 +1,292 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Twelveeee/golib/logger/handler"
+	gormLogger "gorm.io/gorm/logger"
+)
+
+func TestGormAdapter_RetryableErrorClassifier(t *testing.T) {
+	errDeadlock := errors.New("Error 1213: Deadlock found when trying to get lock")
+
+	var out bytes.Buffer
+	adapter := NewGormAdapter(
+		slog.New(handler.NewDefaultHandler(&out, slog.LevelInfo)),
+		WithRetryableErrorClassifier(func(err error) bool {
+			return errors.Is(err, errDeadlock)
+		}),
+	)
+	fc := func() (string, int64) { return "UPDATE users SET name = 'tom'", 0 }
+
+	adapter.Trace(context.Background(), time.Now(), fc, errDeadlock)
+	line := out.String()
+	if !strings.HasPrefix(line, "WARN: ") || !strings.Contains(line, " retryable=true") {
+		t.Errorf("retryable error output = %q, want warn level with retryable=true", line)
+	}
+
+	out.Reset()
+	adapter.Trace(context.Background(), time.Now(), fc, errors.New("Error 1062: Duplicate entry"))
+	line = out.String()
+	if !strings.HasPrefix(line, "ERROR: ") || strings.Contains(line, "retryable=") {
+		t.Errorf("normal error output = %q, want error level without retryable", line)
+	}
+
+	// 日志级别低于 Warn 时不记录可重试的错误，普通错误照常记录
+	errOnly := adapter.LogMode(gormLogger.Error)
+	out.Reset()
+	errOnly.Trace(context.Background(), time.Now(), fc, errDeadlock)
+	if line = out.String(); line != "" {
+		t.Errorf("retryable error at LogMode(Error) output = %q, want nothing", line)
+	}
+	errOnly.Trace(context.Background(), time.Now(), fc, errors.New("Error 1062: Duplicate entry"))
+	if line = out.String(); !strings.HasPrefix(line, "ERROR: ") {
+		t.Errorf("normal error at LogMode(Error) output = %q, want error level", line)
+	}
+}
+
+func TestGormAdapter_SlowQueryCallback(t *testing.T) {
+	var out bytes.Buffer
+	var (
+		called     int
+		gotSQL     string
+		gotElapsed time.Duration
+		gotRows    int64
+	)
+	adapter := NewGormAdapter(
+		slog.New(handler.NewDefaultHandler(&out, slog.LevelInfo)),
+		WithSlowThreshold(10*time.Millisecond),
+		WithSlowQueryCallback(func(_ context.Context, sql string, elapsed time.Duration, rows int64) {
+			called++
+			gotSQL, gotElapsed, gotRows = sql, elapsed, rows
+		}),
+	)
+	fc := func() (string, int64) { return "SELECT *\n  FROM users", 3 }
+
+	adapter.Trace(context.Background(), time.Now().Add(-time.Second), fc, nil)
+	if called != 1 {
+		t.Fatalf("callback called %d times, want 1", called)
+	}
+	if gotSQL != "SELECT * FROM users" || gotRows != 3 || gotElapsed < time.Second {
+		t.Errorf("callback got sql=%q rows=%d elapsed=%v", gotSQL, gotRows, gotElapsed)
+	}
+
+	// 非慢查询不触发回调
+	adapter.Trace(context.Background(), time.Now(), fc, nil)
+	if called != 1 {
+		t.Errorf("callback called %d times for fast query, want 1", called)
+	}
+
+	// 回调不受日志级别影响
+	for _, level := range []gormLogger.LogLevel{gormLogger.Error, gormLogger.Silent} {
+		called = 0
+		out.Reset()
+		adapter.LogMode(level).Trace(context.Background(), time.Now().Add(-time.Second), fc, nil)
+		if called != 1 {
+			t.Errorf("LogMode(%d): callback called %d times, want 1", level, called)
+		}
+		if out.Len() != 0 {
+			t.Errorf("LogMode(%d): output = %q, want no slow query log", level, out.String())
+		}
+	}
+
+	// 未设置回调时慢查询照常记录
+	out.Reset()
+	NewGormAdapter(
+		slog.New(handler.NewDefaultHandler(&out, slog.LevelInfo)),
+		WithSlowThreshold(10*time.Millisecond),
+	).Trace(context.Background(), time.Now().Add(-time.Second), fc, nil)
+	if !strings.Contains(out.String(), "gorm slow query") {
+		t.Errorf("output = %q, want slow query log", out.String())
+	}
+}
+
+func TestGormAdapter_MaxSQLLength(t *testing.T) {
+	longSQL := "INSERT INTO users (name) VALUES " + strings.Repeat("('tom'), ", 1000) + "('jerry')"
+	fc := func() (string, int64) { return longSQL, 1001 }
+
+	var out bytes.Buffer
+	adapter := NewGormAdapter(
+		slog.New(handler.NewDefaultHandler(&out, slog.LevelInfo)),
+		WithMaxSQLLength(40),
+	)
+	adapter.Trace(context.Background(), time.Now(), fc, nil)
+
+	want := " sql=" + longSQL[:40] + "...(truncated) "
+	if !strings.Contains(out.String(), want) {
+		t.Errorf("output = %q, want sql cut to 40 characters", out.String())
+	}
+
+	// 按字符而不是字节截断，不会拆开中文等多字节字符
+	out.Reset()
+	adapter.Trace(context.Background(), time.Now(), func() (string, int64) {
+		return "SELECT * FROM users WHERE name = '" + strings.Repeat("张三", 20) + "'", 1
+	}, nil)
+	want = " sql=SELECT * FROM users WHERE name = '张三张三张三...(truncated) "
+	if !strings.Contains(out.String(), want) {
+		t.Errorf("output = %q, want sql cut to 40 characters", out.String())
+	}
+
+	// 默认不截断
+	out.Reset()
+	NewGormAdapter(slog.New(handler.NewDefaultHandler(&out, slog.LevelInfo))).
+		Trace(context.Background(), time.Now(), fc, nil)
+	if !strings.Contains(out.String(), " sql="+longSQL+" ") {
+		t.Error("SQL should not be truncated by default")
+	}
+}
+
+func TestCleanSQL(t *testing.T) {
+	tests := []struct {
+		name   string
+		sql    string
+		maxLen int
+		want   string
+	}{
+		{"清理空白", " SELECT *\n\tFROM  users \r\n", 0, "SELECT * FROM users"},
+		{"未超出长度", "SELECT 1", 8, "SELECT 1"},
+		{"超出长度截断", "SELECT * FROM users", 8, "SELECT *...(truncated)"},
+		{"按字符数截断", "SELECT '你好世界'", 10, "SELECT '你好...(truncated)"},
+		{"多字节字符不超出长度", "SELECT '你好'", 11, "SELECT '你好'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cleanSQL(tt.sql, tt.maxLen); got != tt.want {
+				t.Errorf("cleanSQL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// traceLikeGorm 按 GORM 的方式调用 Trace：在 fc 中先经过 ParamsFilter 再插值
+func traceLikeGorm(adapter gormLogger.Interface, sql string, vars []interface{}, rows int64, err error) {
+	traceLikeGormContext(context.Background(), adapter, sql, vars, rows, err)
+}
+
+// traceLikeGormContext 同 traceLikeGorm，使用指定的 context
+func traceLikeGormContext(ctx context.Context, adapter gormLogger.Interface, sql string, vars []interface{}, rows int64, err error) {
+	adapter.Trace(ctx, time.Now(), func() (string, int64) {
+		if filter, ok := adapter.(interface {
+			ParamsFilter(context.Context, string, ...interface{}) (string, []interface{})
+		}); ok {
+			sql, vars = filter.ParamsFilter(ctx, sql, vars...)
+		}
+		return gormLogger.ExplainSQL(sql, nil, `'`, vars...), rows
+	}, err)
+}
+
+func TestGormAdapter_Args(t *testing.T) {
+	const stmt = "SELECT * FROM users WHERE name = ? AND age > ?"
+	args := []interface{}{"tom", 18}
+
+	h := handler.NewMemoryHandler(10)
+	adapter := NewGormAdapter(slog.New(h), WithGormArgs(true))
+	traceLikeGorm(adapter, stmt, args, 2, nil)
+	traceLikeGorm(adapter.LogMode(gormLogger.Error), stmt, args, 0, errors.New("Error 1146: table doesn't exist"))
+
+	records := h.Records()
+	if len(records) != 2 {
+		t.Fatalf("got %d records, want 2", len(records))
+	}
+	for _, r := range records {
+		if v, _ := r.Attr("sql"); v.String() != "SELECT * FROM users WHERE name = 'tom' AND age > 18" {
+			t.Errorf("%s: sql = %q, want interpolated sql without marker", r.Message, v)
+		}
+		if v, _ := r.Attr("stmt"); v.String() != stmt {
+			t.Errorf("%s: stmt = %q, want %q", r.Message, v, stmt)
+		}
+		v, ok := r.Attr("args")
+		if got, _ := v.Any().([]interface{}); !ok || len(got) != 2 || got[0] != "tom" || got[1] != 18 {
+			t.Errorf("%s: args = %v, want %v", r.Message, v, args)
+		}
+	}
+	if v, _ := records[0].Attr("rows"); v.Int64() != 2 {
+		t.Errorf("rows = %v, want 2", v)
+	}
+	if _, ok := records[1].Attr("error"); !ok {
+		t.Error("error record should keep the error attr")
+	}
+
+	// 未开启时不输出 stmt 与 args
+	h.Reset()
+	traceLikeGorm(NewGormAdapter(slog.New(h)), stmt, args, 2, nil)
+	r := h.Records()[0]
+	if _, ok := r.Attr("args"); ok {
+		t.Error("args should not be logged by default")
+	}
+	if _, ok := r.Attr("stmt"); ok {
+		t.Error("stmt should not be logged by default")
+	}
+}
+
+func TestGormAdapter_ArgsNoLeak(t *testing.T) {
+	const stmt = "SELECT * FROM users WHERE id = ?"
+	ctx := context.Background()
+	h := handler.NewMemoryHandler(10)
+	adapter := NewGormAdapter(slog.New(h), WithGormArgs(true)).(*GormAdapter)
+
+	// 不在 Trace 中调用（如被其他 logger 包装）时不加标记
+	if sql, _ := adapter.ParamsFilter(ctx, stmt, 1); sql != stmt {
+		t.Errorf("ParamsFilter outside Trace = %q, want %q", sql, stmt)
+	}
+
+	// SQL 被改写、标记不在开头时同样去掉标记
+	adapter.Trace(ctx, time.Now(), func() (string, int64) {
+		sql, vars := adapter.ParamsFilter(ctx, stmt, 1)
+		return "/* rewritten */ " + gormLogger.ExplainSQL(sql, nil, `'`, vars...), 1
+	}, nil)
+	r := h.Records()[0]
+	if v, _ := r.Attr("sql"); v.String() != "/* rewritten */ SELECT * FROM users WHERE id = 1" {
+		t.Errorf("sql = %q, want rewritten sql without marker", v)
+	}
+	if _, ok := r.Attr("args"); !ok {
+		t.Error("args should be logged for rewritten sql")
+	}
+
+	// fc 未使用 ParamsFilter 的结果时，暂存的语句在 Trace 结束时删除
+	adapter.Trace(ctx, time.Now(), func() (string, int64) {
+		_, _ = adapter.ParamsFilter(ctx, stmt, 1)
+		return stmt, 0
+	}, nil)
+	if n := len(adapter.pendingArgs.traces); n != 0 {
+		t.Errorf("pending traces = %d, want 0", n)
+	}
+}
+
+func TestGormAdapter_ArgsConcurrentContext(t *testing.T) {
+	const stmt = "SELECT * FROM users WHERE id = ?"
+	h := handler.NewMemoryHandler(1000)
+	adapter := NewGormAdapter(slog.New(h), WithGormArgs(true))
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			traceLikeGormContext(ctx, adapter, stmt, []interface{}{id}, 1, nil)
+		}(i)
+	}
+	wg.Wait()
+
+	// 同一个 context 上并发的 Trace 各自取回自己的参数
+	for _, r := range h.Records() {
+		sql, _ := r.Attr("sql")
+		args, _ := r.Attr("args")
+		id := args.Any().([]interface{})[0]
+		if want := fmt.Sprintf("SELECT * FROM users WHERE id = %d", id); sql.String() != want {
+			t.Errorf("sql = %q, want %q", sql, want)
+		}
+	}
+	if n := len(adapter.(*GormAdapter).pendingArgs.traces); n != 0 {
+		t.Errorf("pending traces = %d, want 0", n)
+	}
+}
@@ -0,0 +1,24 @@
+package handler
+
+import (
+	"bytes"
+
+	"github.com/Twelveeee/golib/pool"
+)
+
+// getBuffer 从 pool.GlobalBytesPool 获取 Buffer，并防御性地 Reset
+// GlobalBytesPool 可被替换为其他实现，使用方误用（如重复 Put 后继续写入）时也可能残留脏数据，
+// Reset 保证日志行中不会混入上一次的内容
+func getBuffer() *bytes.Buffer {
+	buf := pool.GlobalBytesPool.Get()
+	if buf == nil {
+		return new(bytes.Buffer)
+	}
+	buf.Reset()
+	return buf
+}
+
+// putBuffer 将 Buffer 放回 pool.GlobalBytesPool
+func putBuffer(buf *bytes.Buffer) {
+	pool.GlobalBytesPool.Put(buf)
+}
@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+)
+
+// Builder 以链式调用的方式在 base 之外组合多个装饰 Handler，避免手动嵌套构造函数
+//
+// 无论调用顺序如何，Build 都按以下顺序组合（由外到内）：
+//
+//	级别过滤 -> 条件过滤 -> 采样 -> 附加 context 属性 -> base
+//
+// 开销小、能尽早丢弃日志的装饰放在外层，被丢弃的日志不会再提取 context 属性
+type Builder struct {
+	base     slog.Handler
+	level    slog.Leveler
+	filters  []func(context.Context, slog.Record) bool
+	sampling *SamplingOptions
+	extracts []ContextAttrsFunc
+}
+
+// NewBuilder 创建以 base 为最终输出的 Builder
+func NewBuilder(base slog.Handler) *Builder {
+	return &Builder{base: base}
+}
+
+// WithLevel 只保留 level 及以上级别的日志，可传入 *slog.LevelVar 在运行时动态调整
+// 在 base 自身的级别之外额外生效，多次调用时以最后一次为准
+func (b *Builder) WithLevel(level slog.Leveler) *Builder {
+	b.level = level
+	return b
+}
+
+// WithFilter 只保留 keep 返回 true 的日志，多次调用时需全部满足
+func (b *Builder) WithFilter(keep func(context.Context, slog.Record) bool) *Builder {
+	b.filters = append(b.filters, keep)
+	return b
+}
+
+// WithSampling 按 opts 采样，见 SamplingHandler，多次调用时以最后一次为准
+func (b *Builder) WithSampling(opts *SamplingOptions) *Builder {
+	b.sampling = opts
+	return b
+}
+
+// WithContextAttrs 附加从 context 中提取的属性，见 ContextAttrsHandler，多次调用时累加
+func (b *Builder) WithContextAttrs(extracts ...ContextAttrsFunc) *Builder {
+	b.extracts = append(b.extracts, extracts...)
+	return b
+}
+
+// Build 组合出最终的 Handler，没有配置任何装饰时直接返回 base
+func (b *Builder) Build() slog.Handler {
+	h := b.base
+	if len(b.extracts) > 0 {
+		h = NewContextAttrsHandler(h, b.extracts...)
+	}
+	if b.sampling != nil {
+		h = NewSamplingHandlerWithOptions(h, b.sampling)
+	}
+	for i := len(b.filters) - 1; i >= 0; i-- {
+		h = NewFilterHandler(h, b.filters[i])
+	}
+	if b.level != nil {
+		h = &levelHandler{inner: h, level: b.level}
+	}
+	return h
+}
+
+// levelHandler 在 inner 的级别之外额外按 level 过滤日志
+type levelHandler struct {
+	inner slog.Handler
+	level slog.Leveler
+}
+
+func (h *levelHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= h.level.Level() && h.inner.Enabled(ctx, level)
+}
+
+func (h *levelHandler) Handle(ctx context.Context, r slog.Record) error {
+	if r.Level < h.level.Level() {
+		return nil
+	}
+	return h.inner.Handle(ctx, r)
+}
+
+func (h *levelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &levelHandler{inner: h.inner.WithAttrs(attrs), level: h.level}
+}
+
+func (h *levelHandler) WithGroup(name string) slog.Handler {
+	return &levelHandler{inner: h.inner.WithGroup(name), level: h.level}
+}
@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/Twelveeee/golib/constant"
+)
+
+type userIDKey struct{}
+
+func userIDAttrs(ctx context.Context) []slog.Attr {
+	if id, ok := ctx.Value(userIDKey{}).(string); ok {
+		return []slog.Attr{slog.String("userID", id)}
+	}
+	return nil
+}
+
+func TestBuilder(t *testing.T) {
+	var out bytes.Buffer
+	h := NewBuilder(NewDefaultHandler(&out, slog.LevelDebug)).
+		WithLevel(slog.LevelInfo).
+		WithFilter(func(_ context.Context, r slog.Record) bool {
+			return !strings.HasPrefix(r.Message, "noise")
+		}).
+		WithSampling(&SamplingOptions{Rate: 0}).
+		WithContextAttrs(userIDAttrs).
+		Build()
+	logger := slog.New(h)
+
+	// 采样率为 0，只有 context 中决定保留的日志才会输出
+	keepCtx := context.WithValue(context.Background(), constant.SampleDecisionKey, true)
+	keepCtx = context.WithValue(keepCtx, userIDKey{}, "u42")
+
+	logger.InfoContext(context.Background(), "sampled out")
+	if out.Len() != 0 {
+		t.Fatalf("sampling: output = %q, want dropped", out.String())
+	}
+
+	logger.DebugContext(keepCtx, "too low")
+	if out.Len() != 0 {
+		t.Fatalf("level: output = %q, want dropped", out.String())
+	}
+	if logger.Enabled(keepCtx, slog.LevelDebug) {
+		t.Error("level: debug should be disabled")
+	}
+
+	logger.InfoContext(keepCtx, "noise from gorm")
+	if out.Len() != 0 {
+		t.Fatalf("filter: output = %q, want dropped", out.String())
+	}
+
+	logger.InfoContext(keepCtx, "kept", "k", "v")
+	got := out.String()
+	if !strings.Contains(got, "msg=kept k=v userID=u42") {
+		t.Errorf("output = %q, want record attrs followed by context attrs", got)
+	}
+}
+
+func TestBuilder_LevelVar(t *testing.T) {
+	var out bytes.Buffer
+	var level slog.LevelVar
+	level.Set(slog.LevelWarn)
+	logger := slog.New(NewBuilder(NewDefaultHandler(&out, slog.LevelDebug)).WithLevel(&level).Build())
+
+	logger.Info("before")
+	level.Set(slog.LevelInfo)
+	logger.Info("after")
+
+	if got := out.String(); strings.Contains(got, "msg=before") || !strings.Contains(got, "msg=after") {
+		t.Errorf("output = %q, want only records after lowering the level", got)
+	}
+}
+
+func TestBuilder_NoDecorators(t *testing.T) {
+	base := NewDefaultHandler(&bytes.Buffer{}, slog.LevelInfo)
+	if got := NewBuilder(base).Build(); got != base {
+		t.Errorf("Build() without decorators = %T, want base handler", got)
+	}
+}
@@ -7,13 +7,12 @@ import (
 	"strconv"
 	"strings"
 	"sync"
-
-	"github.com/Twelveeee/golib/pool"
 )
 
 const (
 	callerKey = "caller"
 	stackKey  = "stack"
+	funcKey   = "func"
 )
 
 var (
@@ -37,8 +36,8 @@ func Stack() slog.Attr {
 
 // StackWithSkip 返回调用栈的Field
 func StackWithSkip(skip int) slog.Attr {
-	buf := pool.GlobalBytesPool.Get()
-	defer pool.GlobalBytesPool.Put(buf)
+	buf := getBuffer()
+	defer putBuffer(buf)
 
 	stack := pcsPool.Get().(*stackPtr)
 	defer pcsPool.Put(stack)
@@ -77,8 +76,8 @@ func callerWithSkip(skip int) string {
 		return "unknown"
 	}
 
-	buf := pool.GlobalBytesPool.Get()
-	defer pool.GlobalBytesPool.Put(buf)
+	buf := getBuffer()
+	defer putBuffer(buf)
 
 	buf.WriteString(CallerPathClean(file))
 	buf.WriteByte(':')
@@ -87,19 +86,83 @@ func callerWithSkip(skip int) string {
 	return buf.String()
 }
 
-// writeCallerWithSkip 将调用栈路径直接写入到 buffer 中，避免字符串分配
-// 返回 true 表示成功写入，false 表示获取失败
-func writeCallerWithSkip(buf *bytes.Buffer, skip int) bool {
-	_, file, line, ok := runtime.Caller(skip)
-	if !ok {
-		return false
+// callerFrame 返回 pc（即 slog.Record.PC，日志调用处）向上跳过 skip 层后的帧，获取失败时返回 false
+// 与 runtime.Caller 的固定层级不同，结果不受 Handler 被 MultiHandler 等包装的层数影响
+func callerFrame(pc uintptr, skip int) (runtime.Frame, bool) {
+	if pc == 0 {
+		return runtime.Frame{}, false
+	}
+	if skip <= 0 {
+		frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+		return frame, frame.File != ""
 	}
 
-	buf.WriteString(CallerPathClean(file))
+	stack := pcsPool.Get().(*stackPtr)
+	defer pcsPool.Put(stack)
+
+	frames := framesFromPC(stack, pc)
+	for ; skip > 0; skip-- {
+		if _, more := frames.Next(); !more {
+			return runtime.Frame{}, false
+		}
+	}
+	frame, _ := frames.Next()
+	return frame, frame.File != ""
+}
+
+// writeFrame 将帧的文件路径和行号写入 buffer，如 xxx/xxx/xxx.go:80
+func writeFrame(buf *bytes.Buffer, frame runtime.Frame) {
+	buf.WriteString(CallerPathClean(frame.File))
 	buf.WriteByte(':')
-	buf.WriteString(strconv.Itoa(line))
+	buf.WriteString(strconv.Itoa(frame.Line))
+}
+
+// stackFromPC 返回从 pc 所在的帧开始、向上跳过 skip 层后的调用栈，格式同 StackWithSkip
+func stackFromPC(pc uintptr, skip int) string {
+	buf := getBuffer()
+	defer putBuffer(buf)
+
+	stack := pcsPool.Get().(*stackPtr)
+	defer pcsPool.Put(stack)
+
+	frames := framesFromPC(stack, pc)
+	for ; skip > 0; skip-- {
+		if _, more := frames.Next(); !more {
+			return ""
+		}
+	}
+	for {
+		frame, more := frames.Next()
+		buf.WriteString(frame.File)
+		buf.WriteByte(':')
+		buf.WriteString(strconv.Itoa(frame.Line))
+		if !more {
+			break
+		}
+		buf.WriteByte(';')
+	}
+	return buf.String()
+}
 
-	return true
+// framesFromPC 在当前 goroutine 的调用栈中定位 pc，返回从 pc 所在帧开始的调用栈
+// Handler 在记录日志的 goroutine 中同步执行时总能找到 pc；找不到时（如 Record 被异步处理）只返回 pc 本身所在的帧
+func framesFromPC(stack *stackPtr, pc uintptr) *runtime.Frames {
+	n := runtime.Callers(3, stack.pcs)
+	for i, p := range stack.pcs[:n] {
+		if p == pc {
+			return runtime.CallersFrames(stack.pcs[i:n])
+		}
+	}
+	return runtime.CallersFrames([]uintptr{pc})
+}
+
+// shortFuncName 去掉函数名中的包路径，只保留包名
+// 如 github.com/Twelveeee/golib/logger/handler.Foo -> handler.Foo
+func shortFuncName(name string) string {
+	if idx := strings.LastIndexByte(name, '/'); idx >= 0 {
+		return name[idx+1:]
+	}
+	return name
 }
 
 var pathPrefixes = []string{
@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCaller_WrappedHandlers(t *testing.T) {
+	bases := map[string]func(w *bytes.Buffer) slog.Handler{
+		"default": func(w *bytes.Buffer) slog.Handler { return NewDefaultHandler(w, slog.LevelInfo) },
+		"std": func(w *bytes.Buffer) slog.Handler {
+			return NewStdHandlerWithOptions(w, slog.LevelInfo, &Options{Color: ColorNever})
+		},
+		"pretty": func(w *bytes.Buffer) slog.Handler {
+			return NewPrettyHandlerWithOptions(w, slog.LevelInfo, &Options{Color: ColorNever})
+		},
+	}
+	keepAll := func(context.Context, slog.Record) bool { return true }
+	wrappers := map[string]func(h slog.Handler) slog.Handler{
+		"direct":   func(h slog.Handler) slog.Handler { return h },
+		"multi":    func(h slog.Handler) slog.Handler { return NewMultiHandler(h) },
+		"sampling": func(h slog.Handler) slog.Handler { return NewSamplingHandler(h, 1) },
+		"filter":   func(h slog.Handler) slog.Handler { return NewFilterHandler(h, keepAll) },
+		"counting": func(h slog.Handler) slog.Handler { return NewCountingHandler(h) },
+		"nested": func(h slog.Handler) slog.Handler {
+			return NewCountingHandler(NewFilterHandler(NewMultiHandler(NewSamplingHandler(NewMultiHandler(h), 1)), keepAll))
+		},
+	}
+
+	for baseName, newBase := range bases {
+		for wrapperName, wrap := range wrappers {
+			t.Run(baseName+"/"+wrapperName, func(t *testing.T) {
+				var out bytes.Buffer
+				logger := slog.New(wrap(newBase(&out))).With("k", "v").WithGroup("g")
+
+				_, _, line, _ := runtime.Caller(0)
+				logger.Info("hello") // 与上一行相邻
+
+				want := "callstack_test.go:" + strconv.Itoa(line+1)
+				if !strings.Contains(out.String(), want) {
+					t.Errorf("output = %q, want caller %q", out.String(), want)
+				}
+			})
+		}
+	}
+}
+
+func TestCaller_StackStartsAtCallSite(t *testing.T) {
+	var out bytes.Buffer
+	h := NewDefaultHandlerWithOptions(&out, slog.LevelInfo, &Options{AddStackOnError: true})
+	logger := slog.New(NewMultiHandler(NewCountingHandler(h)))
+
+	_, _, line, _ := runtime.Caller(0)
+	logger.Error("failed") // 与上一行相邻
+
+	_, stack, ok := strings.Cut(out.String(), " stack=")
+	if !ok {
+		t.Fatalf("output = %q, want stack", out.String())
+	}
+	firstFrame, _, _ := strings.Cut(stack, ";")
+	if want := "callstack_test.go:" + strconv.Itoa(line+1); !strings.HasSuffix(firstFrame, want) {
+		t.Errorf("stack first frame = %q, want %q", firstFrame, want)
+	}
+}
+
+func TestCaller_AsyncRecord(t *testing.T) {
+	// Record 在其他 goroutine 中处理时，caller 仍指向日志调用处
+	var pcs [1]uintptr
+	runtime.Callers(1, pcs[:])
+	_, _, line, _ := runtime.Caller(0)
+	r := slog.NewRecord(time.Now(), slog.LevelInfo, "async", pcs[0])
+
+	var out bytes.Buffer
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = NewDefaultHandler(&out, slog.LevelInfo).Handle(context.Background(), r)
+	}()
+	<-done
+
+	if want := "callstack_test.go:" + strconv.Itoa(line-1); !strings.Contains(out.String(), want) {
+		t.Errorf("output = %q, want caller %q", out.String(), want)
+	}
+}
@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strconv"
+)
+
+const attrsKey = "attrs"
+
+// writeCollapsedAttrs 将预设属性和记录中的属性序列化为一个 JSON 对象，以 attrs={...} 的形式写入
+// 属性放在所属分组命名的嵌套对象中，预设属性属于预设时所在的分组；开启 ErrorChain 时错误链以 JSON 数组输出
+// 字符串按 MaxValueLen 截断，时间按 TimeFormat/TimePrecision 格式化，与逐个输出 k=v 时一致
+func writeCollapsedAttrs(buf *bytes.Buffer, opts *Options, state groupState, r slog.Record) {
+	if len(state.attrs) == 0 && r.NumAttrs() == 0 {
+		return
+	}
+
+	root := make(map[string]any, len(state.attrs)+r.NumAttrs())
+	add := func(m map[string]any, attr slog.Attr) {
+		addJSONAttr(opts, m, attr)
+		if chain, ok := opts.errorChainAttr(attr); ok {
+			addJSONAttr(opts, m, chain)
+		}
+	}
+	for _, ga := range state.attrs {
+		add(groupMap(root, ga.groups), ga.attr)
+	}
+	if r.NumAttrs() > 0 {
+		m := groupMap(root, state.groups)
+		r.Attrs(func(attr slog.Attr) bool {
+			add(m, attr)
+			return true
+		})
+	}
+	if len(root) == 0 {
+		return
+	}
+
+	data, err := json.Marshal(root)
+	if err != nil {
+		data = []byte(fmt.Sprintf("%q", err.Error()))
+	}
+	buf.WriteByte(' ')
+	buf.WriteString(attrsKey + "=")
+	buf.Write(data)
+}
+
+// groupMap 返回 groups 对应的嵌套对象，不存在时逐层创建
+// 分组名与已有的普通属性同名时不覆盖该属性，改用 name_2、name_3 等第一个可用的 key
+func groupMap(m map[string]any, groups []string) map[string]any {
+	for _, name := range groups {
+		for i := 1; ; i++ {
+			key := collisionKey(name, i)
+			v, exists := m[key]
+			if !exists {
+				sub := make(map[string]any)
+				m[key] = sub
+				m = sub
+				break
+			}
+			if sub, ok := v.(map[string]any); ok {
+				m = sub
+				break
+			}
+		}
+	}
+	return m
+}
+
+// setJSONLeaf 写入普通属性，同名的普通属性后者覆盖前者；
+// 与已有的分组同名时不覆盖分组，改用 key_2、key_3 等第一个可用的 key
+func setJSONLeaf(m map[string]any, key string, value any) {
+	for i := 1; ; i++ {
+		k := collisionKey(key, i)
+		if _, isGroup := m[k].(map[string]any); !isGroup {
+			m[k] = value
+			return
+		}
+	}
+}
+
+// collisionKey 返回第 i 个候选 key，i 为 1 时即 key 本身
+func collisionKey(key string, i int) string {
+	if i == 1 {
+		return key
+	}
+	return key + "_" + strconv.Itoa(i)
+}
+
+// addJSONAttr 将属性转换为可 JSON 序列化的值后放入 m，同名分组合并，空分组不输出
+func addJSONAttr(opts *Options, m map[string]any, attr slog.Attr) {
+	v := attr.Value.Resolve()
+	if v.Kind() == slog.KindGroup {
+		group := v.Group()
+		if len(group) == 0 {
+			return
+		}
+		if attr.Key != "" {
+			// 空 key 的分组内联到上一级
+			m = groupMap(m, []string{attr.Key})
+		}
+		for _, ga := range group {
+			addJSONAttr(opts, m, ga)
+		}
+		return
+	}
+	setJSONLeaf(m, attr.Key, jsonValue(opts, v))
+}
+
+// jsonValue 将 slog.Value 转换为 JSON 友好的值，格式与文本输出保持一致
+func jsonValue(opts *Options, v slog.Value) any {
+	switch v.Kind() {
+	case slog.KindString:
+		return opts.truncate(v.String())
+	case slog.KindDuration:
+		return v.Duration().String()
+	case slog.KindTime:
+		return opts.formatTime(v.Time())
+	case slog.KindAny:
+		a := v.Any()
+		if err, ok := a.(error); ok {
+			return err.Error()
+		}
+		// 只序列化一次，无法序列化时退化为 fmt 格式
+		data, err := json.Marshal(a)
+		if err != nil {
+			return fmt.Sprint(a)
+		}
+		return json.RawMessage(data)
+	default:
+		return v.Any()
+	}
+}
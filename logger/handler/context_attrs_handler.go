@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+)
+
+// ContextAttrsFunc 从 context 中提取需要附加到日志的属性，如用户 ID、请求路径
+type ContextAttrsFunc func(ctx context.Context) []slog.Attr
+
+// ContextAttrsHandler 在日志交给 inner 处理前，附加从 context 中提取的属性
+// 适合将中间件写入 context 的请求信息统一输出，而不用在每次打日志时手动传入
+type ContextAttrsHandler struct {
+	inner    slog.Handler
+	extracts []ContextAttrsFunc
+}
+
+// NewContextAttrsHandler 创建附加 context 属性的 Handler，属性按 extracts 的顺序附加在日志属性之后
+func NewContextAttrsHandler(inner slog.Handler, extracts ...ContextAttrsFunc) *ContextAttrsHandler {
+	return &ContextAttrsHandler{
+		inner:    inner,
+		extracts: extracts,
+	}
+}
+
+func (h *ContextAttrsHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.inner.Enabled(ctx, level)
+}
+
+func (h *ContextAttrsHandler) Handle(ctx context.Context, r slog.Record) error {
+	if ctx != nil && len(h.extracts) > 0 {
+		// Record 可能被多个 Handler 共享，修改前先复制
+		r = r.Clone()
+		for _, extract := range h.extracts {
+			r.AddAttrs(extract(ctx)...)
+		}
+	}
+	return h.inner.Handle(ctx, r)
+}
+
+func (h *ContextAttrsHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &ContextAttrsHandler{
+		inner:    h.inner.WithAttrs(attrs),
+		extracts: h.extracts,
+	}
+}
+
+func (h *ContextAttrsHandler) WithGroup(name string) slog.Handler {
+	return &ContextAttrsHandler{
+		inner:    h.inner.WithGroup(name),
+		extracts: h.extracts,
+	}
+}
@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+)
+
+// CountingHandler 按日志级别统计日志条数的 Handler，统计后交给 inner 处理
+// 可用于监控日志量，如 error 日志的增长速率
+type CountingHandler struct {
+	inner    slog.Handler
+	counters *levelCounters
+}
+
+// levelCounters 各级别的计数器，WithAttrs/WithGroup 派生出的 Handler 共享同一份计数
+type levelCounters struct {
+	m sync.Map // slog.Level -> *atomic.Int64
+}
+
+func (c *levelCounters) incr(level slog.Level) {
+	v, ok := c.m.Load(level)
+	if !ok {
+		v, _ = c.m.LoadOrStore(level, new(atomic.Int64))
+	}
+	v.(*atomic.Int64).Add(1)
+}
+
+func (c *levelCounters) snapshot() map[slog.Level]int64 {
+	result := make(map[slog.Level]int64)
+	c.m.Range(func(key, value any) bool {
+		result[key.(slog.Level)] = value.(*atomic.Int64).Load()
+		return true
+	})
+	return result
+}
+
+// NewCountingHandler 创建一个统计日志数量的 Handler
+func NewCountingHandler(inner slog.Handler) *CountingHandler {
+	return &CountingHandler{
+		inner:    inner,
+		counters: &levelCounters{},
+	}
+}
+
+// MetricsHandler 用于上报监控指标（如 Prometheus 计数器）时的名称，与 CountingHandler 相同
+type MetricsHandler = CountingHandler
+
+// NewMetricsHandler 创建按级别统计日志条数的 Handler，透明包装 inner，WithAttrs/WithGroup 派生出的 Handler 共享计数
+// 可在上报指标时定期读取 Counts()，等同于 NewCountingHandler
+func NewMetricsHandler(inner slog.Handler) *MetricsHandler {
+	return NewCountingHandler(inner)
+}
+
+// Counts 返回各级别已处理的日志条数快照
+func (h *CountingHandler) Counts() map[slog.Level]int64 {
+	return h.counters.snapshot()
+}
+
+func (h *CountingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.inner.Enabled(ctx, level)
+}
+
+func (h *CountingHandler) Handle(ctx context.Context, r slog.Record) error {
+	h.counters.incr(r.Level)
+	return h.inner.Handle(ctx, r)
+}
+
+func (h *CountingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &CountingHandler{
+		inner:    h.inner.WithAttrs(attrs),
+		counters: h.counters,
+	}
+}
+
+func (h *CountingHandler) WithGroup(name string) slog.Handler {
+	return &CountingHandler{
+		inner:    h.inner.WithGroup(name),
+		counters: h.counters,
+	}
+}
@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCountingHandler_Counts(t *testing.T) {
+	h := NewCountingHandler(NewDefaultHandler(discardWriter{}, slog.LevelDebug))
+	logger := slog.New(h)
+	ctx := context.Background()
+
+	const goroutines = 10
+	var wg sync.WaitGroup
+	wg.Add(goroutines)
+	for i := 0; i < goroutines; i++ {
+		go func() {
+			defer wg.Done()
+			logger.DebugContext(ctx, "debug")
+			logger.InfoContext(ctx, "info")
+			logger.InfoContext(ctx, "info")
+			logger.With("k", "v").WarnContext(ctx, "warn")
+			logger.WithGroup("g").ErrorContext(ctx, "error")
+			logger.ErrorContext(ctx, "error")
+			logger.ErrorContext(ctx, "error")
+		}()
+	}
+	wg.Wait()
+
+	want := map[slog.Level]int64{
+		slog.LevelDebug: goroutines,
+		slog.LevelInfo:  goroutines * 2,
+		slog.LevelWarn:  goroutines,
+		slog.LevelError: goroutines * 3,
+	}
+	got := h.Counts()
+	if len(got) != len(want) {
+		t.Fatalf("Counts() = %v, want %v", got, want)
+	}
+	for level, n := range want {
+		if got[level] != n {
+			t.Errorf("Counts()[%s] = %d, want %d", level, got[level], n)
+		}
+	}
+}
+
+func TestCountingHandler_DisabledLevelNotCounted(t *testing.T) {
+	h := NewCountingHandler(NewDefaultHandler(discardWriter{}, slog.LevelInfo))
+	logger := slog.New(h)
+
+	logger.Debug("debug")
+	logger.Info("info")
+
+	got := h.Counts()
+	if got[slog.LevelDebug] != 0 || got[slog.LevelInfo] != 1 {
+		t.Errorf("Counts() = %v, want only one info record", got)
+	}
+}
+
+func TestCountingHandler_Transparent(t *testing.T) {
+	var wrapped, plain bytes.Buffer
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 0, time.Local)
+	emit := func(h slog.Handler) {
+		h = h.WithAttrs([]slog.Attr{slog.String("app", "demo")}).WithGroup("req")
+		r := slog.NewRecord(ts, slog.LevelWarn, "hello", 0)
+		r.AddAttrs(slog.String("path", "/v1"))
+		if err := h.Handle(context.Background(), r); err != nil {
+			t.Fatalf("Handle() error = %v", err)
+		}
+	}
+
+	counting := NewCountingHandler(NewDefaultHandler(&wrapped, slog.LevelInfo))
+	emit(counting)
+	emit(NewDefaultHandler(&plain, slog.LevelInfo))
+
+	// 包装后的输出应与直接使用 inner 完全一致
+	if wrapped.String() != plain.String() {
+		t.Errorf("output = %q, want %q", wrapped.String(), plain.String())
+	}
+	if got := counting.Counts()[slog.LevelWarn]; got != 1 {
+		t.Errorf("Counts()[WARN] = %d, want 1", got)
+	}
+}
+
+func TestNewMetricsHandler(t *testing.T) {
+	var buf bytes.Buffer
+	h := NewMetricsHandler(NewDefaultHandler(&buf, slog.LevelInfo))
+	logger := slog.New(h)
+
+	logger.Debug("filtered")
+	logger.Info("info")
+	logger.With("app", "demo").Info("info")
+	logger.WithGroup("req").Warn("warn", "path", "/v1")
+	logger.With("k", "v").WithGroup("g").Error("error")
+	logger.Error("error")
+
+	want := map[slog.Level]int64{
+		slog.LevelInfo:  2,
+		slog.LevelWarn:  1,
+		slog.LevelError: 2,
+	}
+	got := h.Counts()
+	if len(got) != len(want) {
+		t.Fatalf("Counts() = %v, want %v", got, want)
+	}
+	for level, n := range want {
+		if got[level] != n {
+			t.Errorf("Counts()[%v] = %d, want %d", level, got[level], n)
+		}
+	}
+
+	// 属性与分组照常传给 inner
+	for _, s := range []string{"msg=info app=demo", "msg=warn req.path=/v1"} {
+		if !strings.Contains(buf.String(), s) {
+			t.Errorf("output = %q, want %q", buf.String(), s)
+		}
+	}
+}
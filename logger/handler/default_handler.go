@@ -6,28 +6,37 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"strconv"
 	"sync"
-	"time"
-
-	"github.com/Twelveeee/golib/constant"
-	"github.com/Twelveeee/golib/pool"
+	"sync/atomic"
 )
 
 // DefaultHandler 自定义日志格式的 Handler
 type DefaultHandler struct {
 	w     io.Writer
 	level slog.Level
-	attrs []slog.Attr
-	group string
+	state groupState
+	opts  Options
+	seq   *atomic.Uint64
 	mu    sync.Mutex
 }
 
 // NewDefaultHandler 创建自定义格式的 Handler
 func NewDefaultHandler(w io.Writer, level slog.Level) *DefaultHandler {
-	return &DefaultHandler{
+	return NewDefaultHandlerWithOptions(w, level, nil)
+}
+
+// NewDefaultHandlerWithOptions 创建自定义格式的 Handler，opts 为 nil 时使用默认配置
+func NewDefaultHandlerWithOptions(w io.Writer, level slog.Level, opts *Options) *DefaultHandler {
+	h := &DefaultHandler{
 		w:     w,
 		level: level,
+		seq:   new(atomic.Uint64),
 	}
+	if opts != nil {
+		h.opts = *opts
+	}
+	return h
 }
 
 func (h *DefaultHandler) Enabled(_ context.Context, level slog.Level) bool {
@@ -35,31 +44,35 @@ func (h *DefaultHandler) Enabled(_ context.Context, level slog.Level) bool {
 }
 
 func (h *DefaultHandler) Handle(ctx context.Context, r slog.Record) error {
-	buf := pool.GlobalBytesPool.Get()
-	defer pool.GlobalBytesPool.Put(buf)
+	buf := getBuffer()
+	defer putBuffer(buf)
 
 	// 添加日志级别
 	buf.WriteString(r.Level.String())
 	buf.WriteString(": ")
 
-	t := r.Time.Format("2006-01-02 15:04:05")
+	t := h.opts.formatTime(r.Time)
 	buf.WriteString(t)
 	buf.WriteByte(' ')
 
-	// 添加 caller 信息
-	if r.PC != 0 {
-		if writeCallerWithSkip(buf, 5) {
+	// 添加 caller 信息，基于 r.PC 解析，不受 Handler 被包装的层数影响
+	if frame, ok := callerFrame(r.PC, h.opts.callerSkip()); ok {
+		writeFrame(buf, frame)
+		buf.WriteByte(' ')
+
+		// 添加函数名
+		if h.opts.AddFuncName && frame.Function != "" {
+			buf.WriteString(funcKey + "=")
+			buf.WriteString(shortFuncName(frame.Function))
 			buf.WriteByte(' ')
 		}
 	}
 
 	// 从 context 中提取 traceID
-	if ctx != nil {
-		if traceID, ok := ctx.Value(constant.TraceIDKey).(string); ok && traceID != "" {
-			buf.WriteString("traceID=")
-			buf.WriteString(traceID)
-			buf.WriteByte(' ')
-		}
+	if traceID, ok := TraceIDFromContext(ctx); ok {
+		buf.WriteString(traceIDKey + "=")
+		buf.WriteString(traceID)
+		buf.WriteByte(' ')
 	}
 
 	// 添加消息
@@ -68,18 +81,39 @@ func (h *DefaultHandler) Handle(ctx context.Context, r slog.Record) error {
 		buf.WriteString(r.Message)
 	}
 
-	// 添加预设的属性
-	for _, attr := range h.attrs {
-		buf.WriteByte(' ')
-		h.appendAttr(buf, attr)
+	// 添加序号
+	if h.opts.AddSequence {
+		buf.WriteString(" " + seqKey + "=")
+		buf.WriteString(strconv.FormatUint(h.seq.Add(1), 10))
 	}
 
-	// 添加记录中的属性
-	r.Attrs(func(attr slog.Attr) bool {
-		buf.WriteByte(' ')
-		h.appendAttr(buf, attr)
-		return true
-	})
+	// 添加唯一 ID
+	if h.opts.AddRecordID {
+		buf.WriteString(" " + recordIDKey + "=")
+		buf.WriteString(recordIDs.next())
+	}
+
+	if h.opts.CollapseAttrs {
+		// 所有属性合并为一个 JSON 对象输出
+		writeCollapsedAttrs(buf, &h.opts, h.state, r)
+	} else {
+		// 添加预设的属性
+		for _, ga := range h.state.attrs {
+			h.writeAttr(buf, ga.prefix, ga.attr)
+		}
+
+		// 添加记录中的属性
+		r.Attrs(func(attr slog.Attr) bool {
+			h.writeAttr(buf, h.state.prefix, attr)
+			return true
+		})
+	}
+
+	// Error 及以上级别自动附加调用栈，与 caller 指向同一层
+	if h.opts.AddStackOnError && r.Level >= slog.LevelError && r.PC != 0 {
+		buf.WriteString(" " + stackKey + "=")
+		buf.WriteString(stackFromPC(r.PC, h.opts.callerSkip()))
+	}
 
 	buf.WriteByte('\n')
 
@@ -89,20 +123,41 @@ func (h *DefaultHandler) Handle(ctx context.Context, r slog.Record) error {
 	return err
 }
 
-func (h *DefaultHandler) appendAttr(buf *bytes.Buffer, attr slog.Attr) {
-	// 处理分组
-	if h.group != "" {
-		buf.WriteString(h.group)
-		buf.WriteByte('.')
+// writeAttr 写入一个属性，key 加上 prefix 分组前缀，与 slog.TextHandler 一致：
+// 分组属性展开为 group.key，空分组不输出，空 key 的分组内联到当前层级
+// 开启 ErrorChain 时在 error 属性之后写入错误链
+func (h *DefaultHandler) writeAttr(buf *bytes.Buffer, prefix string, attr slog.Attr) {
+	attr.Value = attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) {
+		return
+	}
+	if attr.Value.Kind() == slog.KindGroup {
+		if attr.Key != "" {
+			prefix += attr.Key + "."
+		}
+		for _, ga := range attr.Value.Group() {
+			h.writeAttr(buf, prefix, ga)
+		}
+		return
+	}
+
+	buf.WriteByte(' ')
+	h.appendAttr(buf, prefix, attr)
+	if chain, ok := h.opts.errorChainAttr(attr); ok {
+		buf.WriteByte(' ')
+		h.appendAttr(buf, prefix, chain)
 	}
+}
 
+func (h *DefaultHandler) appendAttr(buf *bytes.Buffer, prefix string, attr slog.Attr) {
+	buf.WriteString(prefix)
 	buf.WriteString(attr.Key)
 	buf.WriteByte('=')
 
 	// 根据值类型格式化
 	switch attr.Value.Kind() {
 	case slog.KindString:
-		buf.WriteString(attr.Value.String())
+		buf.WriteString(h.opts.truncate(attr.Value.String()))
 	case slog.KindInt64:
 		fmt.Fprintf(buf, "%d", attr.Value.Int64())
 	case slog.KindUint64:
@@ -114,35 +169,32 @@ func (h *DefaultHandler) appendAttr(buf *bytes.Buffer, attr slog.Attr) {
 	case slog.KindDuration:
 		fmt.Fprint(buf, attr.Value.Duration())
 	case slog.KindTime:
-		buf.WriteString(attr.Value.Time().Format(time.DateTime))
+		buf.WriteString(h.opts.formatTime(attr.Value.Time()))
 	default:
 		fmt.Fprint(buf, attr.Value.Any())
 	}
 }
 
 func (h *DefaultHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
-	newAttrs := make([]slog.Attr, 0, len(h.attrs)+len(attrs))
-	newAttrs = append(newAttrs, h.attrs...)
-	newAttrs = append(newAttrs, attrs...)
-
 	return &DefaultHandler{
 		w:     h.w,
 		level: h.level,
-		attrs: newAttrs,
-		group: h.group,
+		state: h.state.withAttrs(attrs),
+		opts:  h.opts,
+		seq:   h.seq,
 	}
 }
 
 func (h *DefaultHandler) WithGroup(name string) slog.Handler {
-	newGroup := name
-	if h.group != "" {
-		newGroup = h.group + "." + name
+	if name == "" {
+		return h
 	}
 
 	return &DefaultHandler{
 		w:     h.w,
 		level: h.level,
-		attrs: h.attrs,
-		group: newGroup,
+		state: h.state.withGroup(name),
+		opts:  h.opts,
+		seq:   h.seq,
 	}
 }
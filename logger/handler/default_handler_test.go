@@ -0,0 +1,506 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Twelveeee/golib/pool"
+)
+
+// stalePool 总是返回同一个 Buffer 的 BytesPool，Put 时不 Reset 并写入脏数据，
+// 模拟 Put 之后继续写入等误用，且复用是确定的，不依赖 sync.Pool 是否保留对象
+type stalePool struct {
+	buf *bytes.Buffer
+}
+
+func (p *stalePool) Get() *bytes.Buffer { return p.buf }
+
+func (p *stalePool) Put(b *bytes.Buffer) { b.WriteString("stale-content") }
+
+func TestDefaultHandler_NoStaleBufferContent(t *testing.T) {
+	global := pool.GlobalBytesPool
+	pool.GlobalBytesPool = &stalePool{buf: bytes.NewBufferString("stale-content")}
+	t.Cleanup(func() { pool.GlobalBytesPool = global })
+
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 0, time.Local)
+	tests := []struct {
+		name    string
+		handler func(w *bytes.Buffer) slog.Handler
+		want    string
+	}{
+		{
+			name:    "DefaultHandler",
+			handler: func(w *bytes.Buffer) slog.Handler { return NewDefaultHandler(w, slog.LevelInfo) },
+			want:    "INFO: 2024-01-02 03:04:05 msg=fresh n=2\n",
+		},
+		{
+			name: "StdHandler",
+			handler: func(w *bytes.Buffer) slog.Handler {
+				return NewStdHandlerWithOptions(w, slog.LevelInfo, &Options{Color: ColorNever})
+			},
+			want: "INFO: 2024-01-02 03:04:05 msg=fresh n=2\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			h := tt.handler(&out)
+			for i := 1; i <= 2; i++ {
+				out.Reset()
+				r := slog.NewRecord(ts, slog.LevelInfo, "fresh", 0)
+				r.AddAttrs(slog.Int("n", i))
+				if err := h.Handle(context.Background(), r); err != nil {
+					t.Fatalf("Handle() error = %v", err)
+				}
+			}
+			// 第二行复用了第一行 Put 回去的脏 Buffer
+			if out.String() != tt.want {
+				t.Errorf("output = %q, want %q", out.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestDefaultHandler_TimePrecision(t *testing.T) {
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 123456789, time.Local)
+	tests := []struct {
+		name      string
+		precision TimePrecision
+		want      string
+	}{
+		{name: "second", precision: TimePrecisionSecond, want: "INFO: 2024-01-02 03:04:05 msg=hello at=2024-01-02 03:04:05"},
+		{name: "millisecond", precision: TimePrecisionMillisecond, want: "INFO: 2024-01-02 03:04:05.123 msg=hello at=2024-01-02 03:04:05.123"},
+		{name: "microsecond", precision: TimePrecisionMicrosecond, want: "INFO: 2024-01-02 03:04:05.123456 msg=hello at=2024-01-02 03:04:05.123456"},
+		{name: "nanosecond", precision: TimePrecisionNanosecond, want: "INFO: 2024-01-02 03:04:05.123456789 msg=hello at=2024-01-02 03:04:05.123456789"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			h := NewDefaultHandlerWithOptions(&out, slog.LevelInfo, &Options{TimePrecision: tt.precision})
+			// time.Time 类型的属性值使用同样的格式
+			r := slog.NewRecord(ts, slog.LevelInfo, "hello", 0)
+			r.AddAttrs(slog.Time("at", ts))
+			if err := h.Handle(context.Background(), r); err != nil {
+				t.Fatalf("Handle() error = %v", err)
+			}
+			if got := strings.TrimSuffix(out.String(), "\n"); got != tt.want {
+				t.Errorf("output = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDefaultHandler_TimeFormat(t *testing.T) {
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 123456789, time.FixedZone("CST", 8*3600))
+	tests := []struct {
+		name   string
+		format string
+		want   string
+	}{
+		{name: "RFC3339Nano", format: time.RFC3339Nano, want: "INFO: 2024-01-02T03:04:05.123456789+08:00 msg=hello at=2024-01-02T03:04:05.123456789+08:00"},
+		{name: "unix", format: TimeFormatUnix, want: "INFO: " + strconv.FormatInt(ts.Unix(), 10) + " msg=hello at=" + strconv.FormatInt(ts.Unix(), 10)},
+		{name: "unixmilli", format: TimeFormatUnixMilli, want: "INFO: " + strconv.FormatInt(ts.UnixMilli(), 10) + " msg=hello at=" + strconv.FormatInt(ts.UnixMilli(), 10)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			// TimeFormat 优先于 TimePrecision
+			opts := &Options{TimeFormat: tt.format, TimePrecision: TimePrecisionMillisecond}
+			h := NewDefaultHandlerWithOptions(&out, slog.LevelInfo, opts)
+			// time.Time 类型的属性值使用同样的格式
+			r := slog.NewRecord(ts, slog.LevelInfo, "hello", 0)
+			r.AddAttrs(slog.Time("at", ts))
+			if err := h.Handle(context.Background(), r); err != nil {
+				t.Fatalf("Handle() error = %v", err)
+			}
+			if got := strings.TrimSuffix(out.String(), "\n"); got != tt.want {
+				t.Errorf("output = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// lockedBuffer 并发安全的 bytes.Buffer
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestDefaultHandler_AddSequence(t *testing.T) {
+	var out lockedBuffer
+	logger := slog.New(NewDefaultHandlerWithOptions(&out, slog.LevelInfo, &Options{AddSequence: true}))
+
+	const (
+		goroutines = 10
+		iterations = 100
+	)
+	var wg sync.WaitGroup
+	wg.Add(goroutines)
+	for i := 0; i < goroutines; i++ {
+		go func(id int) {
+			defer wg.Done()
+			l := logger.With("goroutine", id)
+			for j := 0; j < iterations; j++ {
+				l.Info("seq test")
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	seqReg := regexp.MustCompile(` seq=(\d+)`)
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != goroutines*iterations {
+		t.Fatalf("got %d lines, want %d", len(lines), goroutines*iterations)
+	}
+	seen := make(map[uint64]bool, len(lines))
+	for _, line := range lines {
+		m := seqReg.FindStringSubmatch(line)
+		if m == nil {
+			t.Fatalf("line without seq: %q", line)
+		}
+		n, _ := strconv.ParseUint(m[1], 10, 64)
+		if seen[n] {
+			t.Fatalf("duplicate seq %d", n)
+		}
+		seen[n] = true
+	}
+	for n := uint64(1); n <= goroutines*iterations; n++ {
+		if !seen[n] {
+			t.Fatalf("seq %d missing, sequence is not contiguous", n)
+		}
+	}
+}
+
+func TestDefaultHandler_AddFuncName(t *testing.T) {
+	var out bytes.Buffer
+	logger := slog.New(NewDefaultHandlerWithOptions(&out, slog.LevelInfo, &Options{AddFuncName: true}))
+	logger.Info("hello")
+
+	want := " func=handler.TestDefaultHandler_AddFuncName "
+	if !strings.Contains(out.String(), want) {
+		t.Errorf("output = %q, want contains %q", out.String(), want)
+	}
+	if strings.Contains(out.String(), "github.com/") {
+		t.Errorf("func name should be trimmed to short form: %q", out.String())
+	}
+
+	out.Reset()
+	slog.New(NewDefaultHandler(&out, slog.LevelInfo)).Info("hello")
+	if strings.Contains(out.String(), "func=") {
+		t.Errorf("func name should be disabled by default: %q", out.String())
+	}
+}
+
+func TestDefaultHandler_CollapseAttrs(t *testing.T) {
+	var out bytes.Buffer
+	logger := slog.New(NewDefaultHandlerWithOptions(&out, slog.LevelInfo, &Options{CollapseAttrs: true}))
+	logger.With("service", "api").Info("hello", "user", "tom", "count", 3, slog.Group("req", "path", "/v1"))
+
+	line := strings.TrimSpace(out.String())
+	idx := strings.Index(line, " attrs=")
+	if idx < 0 {
+		t.Fatalf("output = %q, want attrs= field", line)
+	}
+	if strings.Contains(line[:idx], "user=") {
+		t.Errorf("attrs should not be written as k=v: %q", line)
+	}
+
+	var got map[string]any
+	if err := json.Unmarshal([]byte(line[idx+len(" attrs="):]), &got); err != nil {
+		t.Fatalf("attrs is not a JSON object: %v, line = %q", err, line)
+	}
+	want := map[string]any{
+		"service": "api",
+		"user":    "tom",
+		"count":   float64(3),
+		"req":     map[string]any{"path": "/v1"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("attrs = %v, want %v", got, want)
+	}
+
+	out.Reset()
+	logger.Info("empty")
+	if strings.Contains(out.String(), "attrs=") {
+		t.Errorf("attrs should be omitted when there are no attrs: %q", out.String())
+	}
+}
+
+func TestDefaultHandler_CollapseAttrsOptions(t *testing.T) {
+	var out bytes.Buffer
+	logger := slog.New(NewDefaultHandlerWithOptions(&out, slog.LevelInfo, &Options{
+		CollapseAttrs: true,
+		MaxValueLen:   10,
+		TimeFormat:    time.RFC3339,
+	}))
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	logger.Info("hello",
+		"body", strings.Repeat("a", 100),
+		"at", at,
+		"tags", []string{"x", "y"},
+		"a", "leaf",
+		slog.Group("a", "b", 1),
+	)
+
+	line := strings.TrimSpace(out.String())
+	idx := strings.Index(line, " attrs=")
+	if idx < 0 {
+		t.Fatalf("output = %q, want attrs= field", line)
+	}
+	var got map[string]any
+	if err := json.Unmarshal([]byte(line[idx+len(" attrs="):]), &got); err != nil {
+		t.Fatalf("attrs is not a JSON object: %v, line = %q", err, line)
+	}
+	want := map[string]any{
+		"body": "aaaaaaaaaa...(truncated 90 bytes)",
+		"at":   "2024-01-02T03:04:05Z",
+		"tags": []any{"x", "y"},
+		// 普通属性与分组同名时都保留
+		"a":   "leaf",
+		"a_2": map[string]any{"b": float64(1)},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("attrs = %v, want %v", got, want)
+	}
+
+	// 分组在前时同样保留，同名分组继续合并
+	out.Reset()
+	logger.With(slog.Group("a", "b", 1)).Info("hello", "a", "leaf", slog.Group("a", "c", 2))
+	line = strings.TrimSpace(out.String())
+	if !strings.HasSuffix(line, ` attrs={"a":{"b":1,"c":2},"a_2":"leaf"}`) {
+		t.Errorf("output = %q, want group and leaf both kept", line)
+	}
+}
+
+func TestDefaultHandler_MaxValueLen(t *testing.T) {
+	var out bytes.Buffer
+	logger := slog.New(NewDefaultHandlerWithOptions(&out, slog.LevelInfo, &Options{MaxValueLen: 10}))
+	logger.Info("hello", "body", strings.Repeat("a", 100), "short", "ok", "cn", "你好世界")
+
+	line := out.String()
+	if !strings.Contains(line, " body=aaaaaaaaaa...(truncated 90 bytes)") {
+		t.Errorf("output = %q, want truncated body", line)
+	}
+	if !strings.Contains(line, " short=ok") {
+		t.Errorf("output = %q, short value should not be truncated", line)
+	}
+	// "你好世界" 为 12 字节，截断时不能拆开多字节字符
+	if !strings.Contains(line, " cn=你好世...(truncated 3 bytes)") {
+		t.Errorf("output = %q, want truncated on rune boundary", line)
+	}
+}
+
+func logInner(l *slog.Logger) (line int) {
+	_, _, line, _ = runtime.Caller(0)
+	l.Info("hello") // 与上一行相邻
+	return line + 1
+}
+
+func logOuter(l *slog.Logger) (outerLine, innerLine int) {
+	_, _, outerLine, _ = runtime.Caller(0)
+	innerLine = logInner(l) // 与上一行相邻
+	return outerLine + 1, innerLine
+}
+
+func TestDefaultHandler_CallerSkip(t *testing.T) {
+	var out bytes.Buffer
+	_, innerLine := logOuter(slog.New(NewDefaultHandler(&out, slog.LevelInfo)))
+	want := "default_handler_test.go:" + strconv.Itoa(innerLine) + " "
+	if !strings.Contains(out.String(), want) {
+		t.Errorf("default skip output = %q, want contains %q", out.String(), want)
+	}
+
+	// 多一层封装时 CallerSkip 加 1，caller 指向封装函数的调用方
+	out.Reset()
+	outerLine, _ := logOuter(slog.New(NewDefaultHandlerWithOptions(&out, slog.LevelInfo, &Options{CallerSkip: 1})))
+	want = "default_handler_test.go:" + strconv.Itoa(outerLine) + " "
+	if !strings.Contains(out.String(), want) {
+		t.Errorf("CallerSkip=1 output = %q, want contains %q", out.String(), want)
+	}
+
+	out.Reset()
+	_, _, line, _ := runtime.Caller(0)
+	logOuter(slog.New(NewDefaultHandlerWithOptions(&out, slog.LevelInfo, &Options{CallerSkip: 2, AddFuncName: true})))
+	want = "default_handler_test.go:" + strconv.Itoa(line+1) + " func=handler.TestDefaultHandler_CallerSkip "
+	if !strings.Contains(out.String(), want) {
+		t.Errorf("CallerSkip=2 output = %q, want contains %q", out.String(), want)
+	}
+}
+
+func TestDefaultHandler_AddStackOnError(t *testing.T) {
+	var out bytes.Buffer
+	logger := slog.New(NewDefaultHandlerWithOptions(&out, slog.LevelInfo, &Options{AddStackOnError: true}))
+
+	logger.Info("hello")
+	if strings.Contains(out.String(), "stack=") {
+		t.Errorf("info log should not contain stack: %q", out.String())
+	}
+
+	out.Reset()
+	logger.Error("failed")
+	line := out.String()
+	idx := strings.Index(line, " stack=")
+	if idx < 0 {
+		t.Fatalf("error log should contain stack: %q", line)
+	}
+	// 调用栈应从 caller 所在的帧开始，而不是 handler 或 slog 内部
+	caller := strings.Fields(line)[3]
+	file, lineNo, _ := strings.Cut(caller, ":")
+	firstFrame, _, _ := strings.Cut(line[idx+len(" stack="):], ";")
+	if !strings.HasSuffix(firstFrame, file+":"+lineNo) {
+		t.Errorf("stack first frame = %q, want same as caller %q", firstFrame, caller)
+	}
+}
+
+func TestDefaultHandler_ErrorChain(t *testing.T) {
+	root := errors.New("connection refused")
+	err := fmt.Errorf("load user: %w", fmt.Errorf("query db: %w", root))
+	wantChain := `["load user: query db: connection refused","query db: connection refused","connection refused"]`
+
+	var out bytes.Buffer
+	logger := slog.New(NewDefaultHandlerWithOptions(&out, slog.LevelInfo, &Options{ErrorChain: true}))
+	logger.Error("failed", "error", err, "plain", root)
+
+	line := out.String()
+	if !strings.Contains(line, " error=load user: query db: connection refused error_chain="+wantChain) {
+		t.Errorf("output = %q, want error followed by error_chain", line)
+	}
+	// 没有被包装的 error 不输出错误链
+	if strings.Contains(line, "plain_chain=") {
+		t.Errorf("output = %q, unwrapped error should not have a chain", line)
+	}
+
+	// 合并属性时错误链为 JSON 数组
+	out.Reset()
+	logger = slog.New(NewDefaultHandlerWithOptions(&out, slog.LevelInfo, &Options{ErrorChain: true, CollapseAttrs: true}))
+	logger.Error("failed", "error", err)
+	line = strings.TrimSpace(out.String())
+	var got map[string]any
+	if err := json.Unmarshal([]byte(line[strings.Index(line, " attrs=")+len(" attrs="):]), &got); err != nil {
+		t.Fatalf("attrs is not a JSON object: %v, line = %q", err, line)
+	}
+	want := []any{"load user: query db: connection refused", "query db: connection refused", "connection refused"}
+	if !reflect.DeepEqual(got["error_chain"], want) {
+		t.Errorf("error_chain = %v, want %v", got["error_chain"], want)
+	}
+
+	// 默认不输出错误链
+	out.Reset()
+	slog.New(NewDefaultHandler(&out, slog.LevelInfo)).Error("failed", "error", err)
+	if strings.Contains(out.String(), "error_chain") {
+		t.Errorf("output = %q, error_chain should be off by default", out.String())
+	}
+}
+
+// groupingCases 覆盖 WithAttrs / WithGroup 交替、嵌套分组、空分组等场景
+var groupingCases = []struct {
+	name string
+	log  func(l *slog.Logger)
+}{
+	{"分组前预设的属性不属于分组", func(l *slog.Logger) {
+		l.With("a", 1).WithGroup("g").Info("hello", "b", 2)
+	}},
+	{"嵌套分组", func(l *slog.Logger) {
+		l.With("a", 1).WithGroup("g").With("b", 2).WithGroup("h").With("c", 3).Info("hello", "d", 4)
+	}},
+	{"记录中的分组属性", func(l *slog.Logger) {
+		l.WithGroup("req").Info("hello", "path", "/v1", slog.Group("user", "id", 7, slog.Group("role", "name", "admin")))
+	}},
+	{"分组下没有属性", func(l *slog.Logger) {
+		l.With("a", 1).WithGroup("g").WithGroup("h").Info("hello")
+	}},
+	{"空分组与空 key 分组", func(l *slog.Logger) {
+		l.WithGroup("g").Info("hello", slog.Group("empty"), slog.Group("", "x", 1), "y", 2)
+	}},
+	{"空分组名", func(l *slog.Logger) {
+		l.WithGroup("").With("a", 1).Info("hello", "b", 2)
+	}},
+}
+
+func TestDefaultHandler_GroupingMatchesTextHandler(t *testing.T) {
+	// 标准库输出中去掉 time 和 level，只比较 msg 及之后的属性
+	dropTimeLevel := func(groups []string, a slog.Attr) slog.Attr {
+		if len(groups) == 0 && (a.Key == slog.TimeKey || a.Key == slog.LevelKey) {
+			return slog.Attr{}
+		}
+		return a
+	}
+	bases := map[string]func(w *bytes.Buffer) slog.Handler{
+		"DefaultHandler": func(w *bytes.Buffer) slog.Handler { return NewDefaultHandler(w, slog.LevelInfo) },
+		"StdHandler": func(w *bytes.Buffer) slog.Handler {
+			return NewStdHandlerWithOptions(w, slog.LevelInfo, &Options{Color: ColorNever})
+		},
+	}
+
+	for _, tc := range groupingCases {
+		var want bytes.Buffer
+		tc.log(slog.New(slog.NewTextHandler(&want, &slog.HandlerOptions{ReplaceAttr: dropTimeLevel})))
+
+		for name, newBase := range bases {
+			t.Run(tc.name+"/"+name, func(t *testing.T) {
+				var out bytes.Buffer
+				tc.log(slog.New(newBase(&out)))
+				got := out.String()
+				if idx := strings.Index(got, "msg="); idx >= 0 {
+					got = got[idx:]
+				}
+				if got != want.String() {
+					t.Errorf("output = %q, want %q", got, want.String())
+				}
+			})
+		}
+	}
+}
+
+func TestDefaultHandler_CollapseGroupingMatchesJSONHandler(t *testing.T) {
+	for _, tc := range groupingCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var stdOut, out bytes.Buffer
+			tc.log(slog.New(slog.NewJSONHandler(&stdOut, nil)))
+			tc.log(slog.New(NewDefaultHandlerWithOptions(&out, slog.LevelInfo, &Options{CollapseAttrs: true})))
+
+			var want map[string]any
+			if err := json.Unmarshal(stdOut.Bytes(), &want); err != nil {
+				t.Fatalf("unmarshal JSONHandler output: %v", err)
+			}
+			for _, key := range []string{slog.TimeKey, slog.LevelKey, slog.MessageKey} {
+				delete(want, key)
+			}
+
+			got := map[string]any{}
+			line := strings.TrimSpace(out.String())
+			if idx := strings.Index(line, " attrs="); idx >= 0 {
+				if err := json.Unmarshal([]byte(line[idx+len(" attrs="):]), &got); err != nil {
+					t.Fatalf("attrs is not a JSON object: %v, line = %q", err, line)
+				}
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("attrs = %v, want %v", got, want)
+			}
+		})
+	}
+}
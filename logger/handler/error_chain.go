@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+)
+
+const (
+	// errorChainSuffix 错误链属性的 key 后缀，如 error 属性的错误链输出为 error_chain
+	errorChainSuffix = "_chain"
+	// maxErrorChainDepth 错误链的最大层数，避免 Unwrap 成环时无限展开
+	maxErrorChainDepth = 32
+)
+
+// errorChain 从外到内每一层错误的信息，文本输出为 JSON 数组，避免信息中的空格、冒号造成歧义
+type errorChain []string
+
+func (c errorChain) String() string {
+	data, _ := json.Marshal([]string(c))
+	return string(data)
+}
+
+// errorChainAttr 开启 ErrorChain 时，为包装过的 error 属性生成 <key>_chain 属性
+// 错误链按 errors.Unwrap 逐层展开，没有被包装的 error 不额外输出
+func (o *Options) errorChainAttr(attr slog.Attr) (slog.Attr, bool) {
+	if !o.ErrorChain {
+		return slog.Attr{}, false
+	}
+	v := attr.Value.Resolve()
+	if v.Kind() != slog.KindAny {
+		return slog.Attr{}, false
+	}
+	err, ok := v.Any().(error)
+	if !ok || err == nil {
+		return slog.Attr{}, false
+	}
+
+	var chain errorChain
+	for ; err != nil && len(chain) < maxErrorChainDepth; err = errors.Unwrap(err) {
+		chain = append(chain, err.Error())
+	}
+	if len(chain) < 2 {
+		return slog.Attr{}, false
+	}
+	return slog.Any(attr.Key+errorChainSuffix, chain), true
+}
@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+)
+
+// FilterHandler 按条件过滤日志的 Handler，只有 keep 返回 true 的日志才会交给 inner 处理
+// 可用于在不调整全局日志级别的情况下屏蔽某类噪音日志，如 GORM 的 SQL trace
+type FilterHandler struct {
+	inner slog.Handler
+	keep  func(context.Context, slog.Record) bool
+}
+
+// NewFilterHandler 创建过滤 Handler，keep 为 nil 时保留全部日志
+func NewFilterHandler(inner slog.Handler, keep func(context.Context, slog.Record) bool) *FilterHandler {
+	return &FilterHandler{
+		inner: inner,
+		keep:  keep,
+	}
+}
+
+func (h *FilterHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.inner.Enabled(ctx, level)
+}
+
+func (h *FilterHandler) Handle(ctx context.Context, r slog.Record) error {
+	if h.keep != nil && !h.keep(ctx, r) {
+		return nil
+	}
+	return h.inner.Handle(ctx, r)
+}
+
+func (h *FilterHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &FilterHandler{
+		inner: h.inner.WithAttrs(attrs),
+		keep:  h.keep,
+	}
+}
+
+func (h *FilterHandler) WithGroup(name string) slog.Handler {
+	return &FilterHandler{
+		inner: h.inner.WithGroup(name),
+		keep:  h.keep,
+	}
+}
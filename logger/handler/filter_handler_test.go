@@ -0,0 +1,33 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestFilterHandler(t *testing.T) {
+	var out bytes.Buffer
+	counter := NewCountingHandler(NewDefaultHandler(&out, slog.LevelInfo))
+	dropGorm := func(_ context.Context, r slog.Record) bool {
+		return !strings.HasPrefix(r.Message, "gorm")
+	}
+	logger := slog.New(NewFilterHandler(counter, dropGorm)).With("app", "demo").WithGroup("req")
+
+	logger.Info("gorm trace", "sql", "select 1")
+	logger.Info("gorm slow query", "sql", "select 2")
+	logger.Info("request done", "path", "/v1")
+
+	if got := counter.Counts()[slog.LevelInfo]; got != 1 {
+		t.Errorf("inner handler received %d records, want 1", got)
+	}
+	if strings.Contains(out.String(), "gorm") {
+		t.Errorf("gorm records should be filtered: %q", out.String())
+	}
+	// WithAttrs / WithGroup 需要传递给 inner
+	if !strings.Contains(out.String(), "app=demo") || !strings.Contains(out.String(), " req.path=/v1") {
+		t.Errorf("attrs and group should be propagated to inner: %q", out.String())
+	}
+}
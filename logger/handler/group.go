@@ -0,0 +1,44 @@
+package handler
+
+import "log/slog"
+
+// groupedAttr 通过 WithAttrs 预设的属性，记录预设时所在的分组
+// 与 slog.TextHandler 一致，WithGroup 只对之后添加的属性生效，之前预设的属性保留原来的分组
+type groupedAttr struct {
+	groups []string // 从外到内的分组名
+	prefix string   // 分组以 . 连接并以 . 结尾，如 req.user.，未分组时为空
+	attr   slog.Attr
+}
+
+// groupState Handler 当前所在的分组及预设属性，WithAttrs/WithGroup 时派生新值，不修改原值
+type groupState struct {
+	groups []string
+	prefix string
+	attrs  []groupedAttr
+}
+
+// withAttrs 返回追加了 attrs 的新状态，attrs 属于当前分组
+func (s groupState) withAttrs(attrs []slog.Attr) groupState {
+	if len(attrs) == 0 {
+		return s
+	}
+	newAttrs := make([]groupedAttr, 0, len(s.attrs)+len(attrs))
+	newAttrs = append(newAttrs, s.attrs...)
+	for _, attr := range attrs {
+		newAttrs = append(newAttrs, groupedAttr{groups: s.groups, prefix: s.prefix, attr: attr})
+	}
+	s.attrs = newAttrs
+	return s
+}
+
+// withGroup 返回进入 name 分组后的新状态，name 为空时不变
+func (s groupState) withGroup(name string) groupState {
+	if name == "" {
+		return s
+	}
+	groups := make([]string, 0, len(s.groups)+1)
+	groups = append(groups, s.groups...)
+	s.groups = append(groups, name)
+	s.prefix += name + "."
+	return s
+}
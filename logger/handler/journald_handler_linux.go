@@ -0,0 +1,238 @@
+//go:build linux
+
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"log/slog"
+	"net"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultJournaldSocket systemd-journald 接收原生协议日志的 socket
+const DefaultJournaldSocket = "/run/systemd/journal/socket"
+
+// JournaldOptions JournaldHandler 的配置
+type JournaldOptions struct {
+	// Identifier 写入 SYSLOG_IDENTIFIER 字段，用于 journalctl -t 过滤，为空时不写入
+	Identifier string
+
+	// Level 日志等级
+	Level slog.Level
+
+	// SocketPath journald socket 的路径，为空时使用 DefaultJournaldSocket
+	SocketPath string
+}
+
+// JournaldHandler 以 journald 原生协议输出结构化字段的 Handler，仅支持 Linux
+//
+// 每条日志写入 MESSAGE、PRIORITY、CODE_FILE、CODE_LINE、CODE_FUNC 等字段，
+// 属性 key 转换为大写，非字母数字的字符替换为 _，分组以 _ 连接，如 req.path 对应 REQ_PATH；
+// 以数字开头或与 MESSAGE、PRIORITY、CODE_FILE 等保留字段同名的 key 加上 X_ 前缀，如 2fa 对应 X_2FA
+// 单条日志不能超过 socket 的数据报大小限制，超出时写入失败
+type JournaldHandler struct {
+	conn       net.Conn
+	level      slog.Level
+	identifier string
+	state      groupState
+	mu         *sync.Mutex // 派生出的 Handler 共享同一个连接
+}
+
+// NewJournaldHandler 连接 journald socket 并创建 Handler，opts 为 nil 时使用默认配置
+func NewJournaldHandler(opts *JournaldOptions) (*JournaldHandler, error) {
+	if opts == nil {
+		opts = &JournaldOptions{}
+	}
+	socket := opts.SocketPath
+	if socket == "" {
+		socket = DefaultJournaldSocket
+	}
+
+	conn, err := net.Dial("unixgram", socket)
+	if err != nil {
+		return nil, fmt.Errorf("connect journald socket %q failed: %w", socket, err)
+	}
+
+	return &JournaldHandler{
+		conn:       conn,
+		level:      opts.Level,
+		identifier: opts.Identifier,
+		mu:         &sync.Mutex{},
+	}, nil
+}
+
+func (h *JournaldHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level
+}
+
+func (h *JournaldHandler) Handle(ctx context.Context, r slog.Record) error {
+	buf := getBuffer()
+	defer putBuffer(buf)
+
+	writeJournaldField(buf, "MESSAGE", r.Message)
+	writeJournaldField(buf, "PRIORITY", strconv.Itoa(journaldPriority(r.Level)))
+	if h.identifier != "" {
+		writeJournaldField(buf, "SYSLOG_IDENTIFIER", h.identifier)
+	}
+	if r.PC != 0 {
+		frame, _ := runtime.CallersFrames([]uintptr{r.PC}).Next()
+		writeJournaldField(buf, "CODE_FILE", frame.File)
+		writeJournaldField(buf, "CODE_LINE", strconv.Itoa(frame.Line))
+		writeJournaldField(buf, "CODE_FUNC", frame.Function)
+	}
+	if traceID, ok := TraceIDFromContext(ctx); ok {
+		writeJournaldField(buf, journaldKey(traceIDKey), traceID)
+	}
+
+	for _, ga := range h.state.attrs {
+		h.appendAttr(buf, strings.Join(ga.groups, "_"), ga.attr)
+	}
+	if r.NumAttrs() > 0 {
+		group := strings.Join(h.state.groups, "_")
+		r.Attrs(func(attr slog.Attr) bool {
+			h.appendAttr(buf, group, attr)
+			return true
+		})
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := h.conn.Write(buf.Bytes())
+	return err
+}
+
+// appendAttr 写入属性字段，分组属性展开为以 _ 连接的多个字段
+func (h *JournaldHandler) appendAttr(buf *bytes.Buffer, prefix string, attr slog.Attr) {
+	v := attr.Value.Resolve()
+	key := attr.Key
+	if prefix != "" && key != "" {
+		key = prefix + "_" + key
+	} else if prefix != "" {
+		key = prefix
+	}
+
+	if v.Kind() == slog.KindGroup {
+		for _, ga := range v.Group() {
+			h.appendAttr(buf, key, ga)
+		}
+		return
+	}
+	if key == "" {
+		return
+	}
+	writeJournaldField(buf, journaldAttrKey(key), formatValue(v))
+}
+
+// Close 关闭与 journald 的连接，派生出的 Handler 共享该连接
+func (h *JournaldHandler) Close() error {
+	return h.conn.Close()
+}
+
+func (h *JournaldHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &JournaldHandler{
+		conn:       h.conn,
+		level:      h.level,
+		identifier: h.identifier,
+		state:      h.state.withAttrs(attrs),
+		mu:         h.mu,
+	}
+}
+
+func (h *JournaldHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	return &JournaldHandler{
+		conn:       h.conn,
+		level:      h.level,
+		identifier: h.identifier,
+		state:      h.state.withGroup(name),
+		mu:         h.mu,
+	}
+}
+
+// journaldPriority 将日志级别转换为 syslog 优先级
+func journaldPriority(level slog.Level) int {
+	switch {
+	case level >= slog.LevelError:
+		return 3 // err
+	case level >= slog.LevelWarn:
+		return 4 // warning
+	case level >= slog.LevelInfo:
+		return 6 // info
+	default:
+		return 7 // debug
+	}
+}
+
+// journaldKey 转换为 journald 合法的字段名：大写字母、数字和下划线，且不能以下划线开头
+func journaldKey(key string) string {
+	key = strings.ToUpper(key)
+	b := []byte(key)
+	for i, c := range b {
+		if (c < 'A' || c > 'Z') && (c < '0' || c > '9') {
+			b[i] = '_'
+		}
+	}
+	key = strings.TrimLeft(string(b), "_")
+	if key == "" {
+		return "EMPTY"
+	}
+	return key
+}
+
+// journaldReservedFields Handler 自身写入或 journald 赋予特殊含义的字段，属性不能使用这些字段名
+var journaldReservedFields = map[string]struct{}{
+	"MESSAGE":            {},
+	"MESSAGE_ID":         {},
+	"PRIORITY":           {},
+	"CODE_FILE":          {},
+	"CODE_LINE":          {},
+	"CODE_FUNC":          {},
+	"ERRNO":              {},
+	"INVOCATION_ID":      {},
+	"USER_INVOCATION_ID": {},
+	"SYSLOG_FACILITY":    {},
+	"SYSLOG_IDENTIFIER":  {},
+	"SYSLOG_PID":         {},
+	"SYSLOG_TIMESTAMP":   {},
+	"SYSLOG_RAW":         {},
+	"DOCUMENTATION":      {},
+	"TID":                {},
+	"UNIT":               {},
+	"USER_UNIT":          {},
+	"TRACE_ID":           {},
+}
+
+// journaldAttrKey 转换属性 key 为字段名，journald 要求字段名以字母开头（以数字开头的字段会导致整条日志被丢弃），
+// 且属性不应覆盖保留字段，这两种情况加上 X_ 前缀
+func journaldAttrKey(key string) string {
+	key = journaldKey(key)
+	if _, reserved := journaldReservedFields[key]; reserved || (key[0] >= '0' && key[0] <= '9') {
+		return "X_" + key
+	}
+	return key
+}
+
+// writeJournaldField 按 journald 原生协议写入一个字段
+// 值中不含换行时写为 KEY=value\n，否则写为 KEY\n + 8 字节小端长度 + value + \n
+func writeJournaldField(buf *bytes.Buffer, key, value string) {
+	buf.WriteString(key)
+	if !strings.Contains(value, "\n") {
+		buf.WriteByte('=')
+		buf.WriteString(value)
+		buf.WriteByte('\n')
+		return
+	}
+	buf.WriteByte('\n')
+	_ = binary.Write(buf, binary.LittleEndian, uint64(len(value)))
+	buf.WriteString(value)
+	buf.WriteByte('\n')
+}
@@ -0,0 +1,148 @@
+//go:build linux
+
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"log/slog"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// listenFakeJournald 创建一个模拟 journald 的 unixgram socket
+func listenFakeJournald(t *testing.T) (*net.UnixConn, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "journal.socket")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		t.Fatalf("listen fake journald: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn, path
+}
+
+func readDatagram(t *testing.T, conn *net.UnixConn) []byte {
+	t.Helper()
+	_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+	buf := make([]byte, 64*1024)
+	n, err := conn.Read(buf)
+	if err != nil {
+		t.Fatalf("read datagram: %v", err)
+	}
+	return buf[:n]
+}
+
+func TestJournaldHandler(t *testing.T) {
+	conn, path := listenFakeJournald(t)
+	h, err := NewJournaldHandler(&JournaldOptions{Identifier: "demo", Level: slog.LevelInfo, SocketPath: path})
+	if err != nil {
+		t.Fatalf("NewJournaldHandler() error = %v", err)
+	}
+	defer h.Close()
+
+	logger := slog.New(h).With("service", "api").WithGroup("req")
+	logger.Warn("hello", "path", "/v1", "user-id", 42)
+
+	got := string(readDatagram(t, conn))
+	for _, want := range []string{
+		"MESSAGE=hello\n",
+		"PRIORITY=4\n",
+		"SYSLOG_IDENTIFIER=demo\n",
+		"SERVICE=api\n",
+		"REQ_PATH=/v1\n",
+		"REQ_USER_ID=42\n",
+		"CODE_FUNC=github.com/Twelveeee/golib/logger/handler.TestJournaldHandler\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("datagram = %q, want contains %q", got, want)
+		}
+	}
+	if !strings.Contains(got, "CODE_FILE=") || !strings.Contains(got, "CODE_LINE=") {
+		t.Errorf("datagram = %q, want code location fields", got)
+	}
+
+	// Debug 低于配置的级别，不应写入
+	if slog.New(h).Enabled(context.Background(), slog.LevelDebug) {
+		t.Error("debug level should be disabled")
+	}
+}
+
+func TestJournaldHandler_MultilineValue(t *testing.T) {
+	conn, path := listenFakeJournald(t)
+	h, err := NewJournaldHandler(&JournaldOptions{SocketPath: path})
+	if err != nil {
+		t.Fatalf("NewJournaldHandler() error = %v", err)
+	}
+	defer h.Close()
+
+	msg := "line1\nline2"
+	if err := h.Handle(context.Background(), slog.NewRecord(time.Now(), slog.LevelError, msg, 0)); err != nil {
+		t.Fatalf("Handle() error = %v", err)
+	}
+
+	// 含换行的值使用二进制格式：KEY\n + 8 字节小端长度 + value + \n
+	var want bytes.Buffer
+	want.WriteString("MESSAGE\n")
+	_ = binary.Write(&want, binary.LittleEndian, uint64(len(msg)))
+	want.WriteString(msg + "\n")
+	want.WriteString("PRIORITY=3\n")
+
+	if got := readDatagram(t, conn); !bytes.Equal(got, want.Bytes()) {
+		t.Errorf("datagram = %q, want %q", got, want.Bytes())
+	}
+}
+
+func TestJournaldHandler_AttrKeys(t *testing.T) {
+	conn, path := listenFakeJournald(t)
+	h, err := NewJournaldHandler(&JournaldOptions{SocketPath: path})
+	if err != nil {
+		t.Fatalf("NewJournaldHandler() error = %v", err)
+	}
+	defer h.Close()
+
+	slog.New(h).Info("hello", "2fa", true, "message", "user", "priority", "high", "code_file", "x.go", "_pid", 1)
+
+	got := string(readDatagram(t, conn))
+	for _, want := range []string{
+		"MESSAGE=hello\n",
+		"PRIORITY=6\n",
+		"X_2FA=true\n",
+		"X_MESSAGE=user\n",
+		"X_PRIORITY=high\n",
+		"X_CODE_FILE=x.go\n",
+		"PID=1\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("datagram = %q, want contains %q", got, want)
+		}
+	}
+	for _, field := range []string{"MESSAGE", "PRIORITY", "CODE_FILE"} {
+		if n := strings.Count("\n"+got, "\n"+field+"="); n != 1 {
+			t.Errorf("%s appears %d times, want 1: %q", field, n, got)
+		}
+	}
+	if strings.Contains("\n"+got, "\n2FA=") {
+		t.Errorf("datagram = %q, field names must not start with a digit", got)
+	}
+}
+
+func TestJournaldAttrKey(t *testing.T) {
+	tests := map[string]string{
+		"user.id":  "USER_ID",
+		"2fa":      "X_2FA",
+		"message":  "X_MESSAGE",
+		"trace_id": "X_TRACE_ID",
+		"__":       "EMPTY",
+		"_1st":     "X_1ST",
+	}
+	for key, want := range tests {
+		if got := journaldAttrKey(key); got != want {
+			t.Errorf("journaldAttrKey(%q) = %q, want %q", key, got, want)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/Twelveeee/golib/ring"
+)
+
+// MemoryRecord MemoryHandler 保存的一条日志
+type MemoryRecord struct {
+	Time    time.Time
+	Level   slog.Level
+	Message string
+	// Attrs 预设属性在前、记录属性在后，分组属性展开为 "group.key"
+	Attrs []slog.Attr
+}
+
+// Attr 返回 key 对应的属性值，分组属性使用 "group.key" 查找
+func (r MemoryRecord) Attr(key string) (slog.Value, bool) {
+	for _, attr := range r.Attrs {
+		if attr.Key == key {
+			return attr.Value, true
+		}
+	}
+	return slog.Value{}, false
+}
+
+// MemoryHandler 在内存中保留最近 capacity 条日志的 Handler，主要用于测试中按字段断言日志内容
+type MemoryHandler struct {
+	state groupState
+	ring  *ring.Buffer[MemoryRecord] // WithAttrs/WithGroup 派生出的 Handler 共享同一份
+}
+
+// NewMemoryHandler 创建保留最近 capacity 条日志的 Handler，capacity <= 0 时容量为 1
+// 处理所有级别的日志，需要按级别过滤时可配合 Builder.WithLevel 使用
+func NewMemoryHandler(capacity int) *MemoryHandler {
+	return &MemoryHandler{
+		ring: ring.New[MemoryRecord](capacity),
+	}
+}
+
+// Records 按从旧到新的顺序返回保存的日志
+func (h *MemoryHandler) Records() []MemoryRecord {
+	return h.ring.Slice()
+}
+
+// Reset 清空保存的日志
+func (h *MemoryHandler) Reset() {
+	h.ring.Reset()
+}
+
+func (h *MemoryHandler) Enabled(context.Context, slog.Level) bool {
+	return true
+}
+
+func (h *MemoryHandler) Handle(_ context.Context, r slog.Record) error {
+	attrs := make([]slog.Attr, 0, len(h.state.attrs)+r.NumAttrs())
+	for _, ga := range h.state.attrs {
+		attrs = appendFlatAttr(attrs, ga.prefix, ga.attr)
+	}
+	r.Attrs(func(attr slog.Attr) bool {
+		attrs = appendFlatAttr(attrs, h.state.prefix, attr)
+		return true
+	})
+
+	h.ring.Push(MemoryRecord{
+		Time:    r.Time,
+		Level:   r.Level,
+		Message: r.Message,
+		Attrs:   attrs,
+	})
+	return nil
+}
+
+func (h *MemoryHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &MemoryHandler{
+		state: h.state.withAttrs(attrs),
+		ring:  h.ring,
+	}
+}
+
+func (h *MemoryHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	return &MemoryHandler{
+		state: h.state.withGroup(name),
+		ring:  h.ring,
+	}
+}
+
+// appendFlatAttr 将属性展开为 "group.key" 形式追加到 attrs，prefix 为以 . 结尾的分组前缀
+// 空 key 的分组内联到当前层级，空分组与空 key 的普通属性不输出
+func appendFlatAttr(attrs []slog.Attr, prefix string, attr slog.Attr) []slog.Attr {
+	attr.Value = attr.Value.Resolve()
+	if attr.Value.Kind() != slog.KindGroup {
+		if attr.Key == "" {
+			return attrs
+		}
+		return append(attrs, slog.Attr{Key: prefix + attr.Key, Value: attr.Value})
+	}
+
+	if attr.Key != "" {
+		prefix += attr.Key + "."
+	}
+	for _, a := range attr.Value.Group() {
+		attrs = appendFlatAttr(attrs, prefix, a)
+	}
+	return attrs
+}
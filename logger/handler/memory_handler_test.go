@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"testing"
+)
+
+func TestMemoryHandler(t *testing.T) {
+	h := NewMemoryHandler(3)
+	logger := slog.New(h).With("service", "api")
+
+	logger.Debug("first")
+	logger.Info("second", "k", 1)
+	logger.WithGroup("req").Warn("third", "path", "/v1", slog.Group("user", "id", 7))
+	logger.Error("fourth", "err", errors.New("boom"))
+
+	records := h.Records()
+	if len(records) != 3 {
+		t.Fatalf("len(Records()) = %d, want 3 (oldest evicted)", len(records))
+	}
+
+	wantMsgs := []string{"second", "third", "fourth"}
+	wantLevels := []slog.Level{slog.LevelInfo, slog.LevelWarn, slog.LevelError}
+	for i, r := range records {
+		if r.Message != wantMsgs[i] || r.Level != wantLevels[i] {
+			t.Errorf("records[%d] = %s %q, want %s %q", i, r.Level, r.Message, wantLevels[i], wantMsgs[i])
+		}
+		if v, ok := r.Attr("service"); !ok || v.String() != "api" {
+			t.Errorf("records[%d] service = %v, %v, want api", i, v, ok)
+		}
+	}
+
+	if v, ok := records[0].Attr("k"); !ok || v.Int64() != 1 {
+		t.Errorf("k = %v, %v, want 1", v, ok)
+	}
+	if v, ok := records[1].Attr("req.path"); !ok || v.String() != "/v1" {
+		t.Errorf("req.path = %v, %v, want /v1", v, ok)
+	}
+	if v, ok := records[1].Attr("req.user.id"); !ok || v.Int64() != 7 {
+		t.Errorf("req.user.id = %v, %v, want 7", v, ok)
+	}
+	if v, ok := records[2].Attr("err"); !ok || v.Any().(error).Error() != "boom" {
+		t.Errorf("err = %v, %v, want boom", v, ok)
+	}
+	if _, ok := records[2].Attr("missing"); ok {
+		t.Error("missing attr should not be found")
+	}
+
+	h.Reset()
+	if got := h.Records(); len(got) != 0 {
+		t.Errorf("Records() after Reset = %v, want empty", got)
+	}
+}
+
+func TestMemoryHandler_Groups(t *testing.T) {
+	h := NewMemoryHandler(1)
+	// WithGroup("") 不改变分组；WithGroup 只对之后添加的属性生效
+	logger := slog.New(h).With("app", "demo").WithGroup("a").WithGroup("").With("b", 1).WithGroup("c")
+	logger.Info("grouped", "d", 2, slog.Group("", "e", 3), slog.Group("f"))
+
+	got := h.Records()[0].Attrs
+	want := []string{"app", "a.b", "a.c.d", "a.c.e"}
+	if len(got) != len(want) {
+		t.Fatalf("Attrs = %v, want keys %v", got, want)
+	}
+	for i, key := range want {
+		if got[i].Key != key {
+			t.Errorf("Attrs[%d].Key = %q, want %q", i, got[i].Key, key)
+		}
+	}
+}
@@ -2,57 +2,84 @@ package handler
 
 import (
 	"context"
+	"errors"
 	"io"
 	"log/slog"
 )
 
-// MultiHandler 可以同时使用多个 handler
+// MultiHandler 可以同时使用多个 handler，NewLogger 在 Debug 级别下也通过它同时输出到文件与标准输出
 type MultiHandler struct {
-	handlers []slog.Handler
+	branches []LeveledHandler
+}
+
+// LeveledHandler MultiHandler 的一个分支，Level 为该分支的最低级别，由 MultiHandler 在 Handler 自身的级别之外额外过滤
+// Level 为 nil 时不额外过滤；可传入 *slog.LevelVar 在运行时动态调整
+type LeveledHandler struct {
+	Handler slog.Handler
+	Level   slog.Leveler
 }
 
 // NewMultiHandler 创建一个多 handler
 func NewMultiHandler(handlers ...slog.Handler) *MultiHandler {
-	return &MultiHandler{
-		handlers: handlers,
+	branches := make([]LeveledHandler, len(handlers))
+	for i, handler := range handlers {
+		branches[i] = LeveledHandler{Handler: handler}
+	}
+	return &MultiHandler{branches: branches}
+}
+
+// NewMultiHandlerWithLevels 创建每个分支有独立最低级别的多 handler，
+// 如 Debug 及以上写入文件、Warn 及以上输出到标准输出
+func NewMultiHandlerWithLevels(branches ...LeveledHandler) *MultiHandler {
+	return &MultiHandler{branches: append([]LeveledHandler(nil), branches...)}
+}
+
+// enabled 判断分支是否接收该级别的日志
+func (b LeveledHandler) enabled(ctx context.Context, level slog.Level) bool {
+	if b.Level != nil && level < b.Level.Level() {
+		return false
 	}
+	return b.Handler.Enabled(ctx, level)
 }
 
 func (h *MultiHandler) Enabled(ctx context.Context, level slog.Level) bool {
-	// 只要有一个 handler 启用就返回 true
-	for _, handler := range h.handlers {
-		if handler.Enabled(ctx, level) {
+	// 只要有一个分支启用就返回 true
+	for _, branch := range h.branches {
+		if branch.enabled(ctx, level) {
 			return true
 		}
 	}
 	return false
 }
 
+// Handle 依次交给每个启用的分支处理，某个分支出错不影响其余分支，所有错误通过 errors.Join 合并返回
+// 每个分支收到的是 Record 的副本，分支内修改 Record（如添加属性）不会影响其他分支
 func (h *MultiHandler) Handle(ctx context.Context, r slog.Record) error {
-	for _, handler := range h.handlers {
-		if handler.Enabled(ctx, r.Level) {
-			if err := handler.Handle(ctx, r); err != nil {
-				return err
+	var errs []error
+	for _, branch := range h.branches {
+		if branch.enabled(ctx, r.Level) {
+			if err := branch.Handler.Handle(ctx, r.Clone()); err != nil {
+				errs = append(errs, err)
 			}
 		}
 	}
-	return nil
+	return errors.Join(errs...)
 }
 
 func (h *MultiHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
-	newHandlers := make([]slog.Handler, len(h.handlers))
-	for i, handler := range h.handlers {
-		newHandlers[i] = handler.WithAttrs(attrs)
+	newBranches := make([]LeveledHandler, len(h.branches))
+	for i, branch := range h.branches {
+		newBranches[i] = LeveledHandler{Handler: branch.Handler.WithAttrs(attrs), Level: branch.Level}
 	}
-	return &MultiHandler{handlers: newHandlers}
+	return &MultiHandler{branches: newBranches}
 }
 
 func (h *MultiHandler) WithGroup(name string) slog.Handler {
-	newHandlers := make([]slog.Handler, len(h.handlers))
-	for i, handler := range h.handlers {
-		newHandlers[i] = handler.WithGroup(name)
+	newBranches := make([]LeveledHandler, len(h.branches))
+	for i, branch := range h.branches {
+		newBranches[i] = LeveledHandler{Handler: branch.Handler.WithGroup(name), Level: branch.Level}
 	}
-	return &MultiHandler{handlers: newHandlers}
+	return &MultiHandler{branches: newBranches}
 }
 
 // nopCloser 包装一个 io.Writer 使其实现 io.WriteCloser
@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMultiHandlerWithLevels(t *testing.T) {
+	var file, stdout bytes.Buffer
+	var stdoutLevel slog.LevelVar
+	stdoutLevel.Set(slog.LevelWarn)
+
+	h := NewMultiHandlerWithLevels(
+		LeveledHandler{Handler: NewDefaultHandler(&file, slog.LevelDebug), Level: slog.LevelDebug},
+		LeveledHandler{Handler: NewStdHandlerWithOptions(&stdout, slog.LevelDebug, &Options{Color: ColorNever}), Level: &stdoutLevel},
+	)
+	logger := slog.New(h).With("app", "demo")
+
+	logger.Info("only file")
+	if !strings.Contains(file.String(), "msg=only file app=demo") {
+		t.Errorf("file = %q, want info record", file.String())
+	}
+	if stdout.Len() != 0 {
+		t.Errorf("stdout = %q, want info filtered by branch level", stdout.String())
+	}
+
+	logger.Warn("both")
+	if !strings.Contains(file.String(), "msg=both") || !strings.Contains(stdout.String(), "msg=both app=demo") {
+		t.Errorf("warn should reach both branches, file = %q, stdout = %q", file.String(), stdout.String())
+	}
+
+	// 分支级别可以动态调整
+	stdoutLevel.Set(slog.LevelInfo)
+	logger.Info("after lowering")
+	if !strings.Contains(stdout.String(), "msg=after lowering") {
+		t.Errorf("stdout = %q, want info after lowering branch level", stdout.String())
+	}
+
+	// 任一分支接收即启用
+	ctx := context.Background()
+	if !h.Enabled(ctx, slog.LevelDebug) {
+		t.Error("Enabled(debug) = false, want true because the file branch accepts it")
+	}
+	onlyWarn := NewMultiHandlerWithLevels(LeveledHandler{Handler: NewDefaultHandler(&file, slog.LevelDebug), Level: slog.LevelWarn})
+	if onlyWarn.Enabled(ctx, slog.LevelInfo) {
+		t.Error("Enabled(info) = true, want false when every branch requires warn")
+	}
+}
+
+// errHandler 记录调用次数并返回固定错误的 Handler
+type errHandler struct {
+	err   error
+	calls int
+}
+
+func (h *errHandler) Enabled(context.Context, slog.Level) bool { return true }
+func (h *errHandler) Handle(context.Context, slog.Record) error {
+	h.calls++
+	return h.err
+}
+func (h *errHandler) WithAttrs([]slog.Attr) slog.Handler { return h }
+func (h *errHandler) WithGroup(string) slog.Handler      { return h }
+
+func TestMultiHandler_AggregateErrors(t *testing.T) {
+	errDiskFull := errors.New("disk full")
+	failing := &errHandler{err: errDiskFull}
+	var out1, out2 bytes.Buffer
+
+	logger := slog.New(NewMultiHandler(
+		failing,
+		NewDefaultHandler(&out1, slog.LevelInfo),
+		NewDefaultHandler(&out2, slog.LevelInfo),
+	))
+	err := logger.Handler().Handle(context.Background(), slog.NewRecord(time.Now(), slog.LevelInfo, "hello", 0))
+
+	if failing.calls != 1 {
+		t.Errorf("failing handler calls = %d, want 1", failing.calls)
+	}
+	if !strings.Contains(out1.String(), "msg=hello") || !strings.Contains(out2.String(), "msg=hello") {
+		t.Errorf("handlers after the failing one should still receive the record, out1 = %q, out2 = %q", out1.String(), out2.String())
+	}
+	if !errors.Is(err, errDiskFull) {
+		t.Errorf("Handle() error = %v, want %v", err, errDiskFull)
+	}
+
+	// 多个分支出错时全部返回
+	errTimeout := errors.New("timeout")
+	err = NewMultiHandler(&errHandler{err: errDiskFull}, &errHandler{err: errTimeout}).
+		Handle(context.Background(), slog.NewRecord(time.Now(), slog.LevelInfo, "hello", 0))
+	if !errors.Is(err, errDiskFull) || !errors.Is(err, errTimeout) {
+		t.Errorf("Handle() error = %v, want both errors", err)
+	}
+}
+
+// tagHandler 在 Record 上添加 branch 属性后交给 inner，用于验证分支间互不影响
+type tagHandler struct {
+	slog.Handler
+	name string
+}
+
+func (h *tagHandler) Handle(ctx context.Context, r slog.Record) error {
+	r.AddAttrs(slog.String("branch", h.name))
+	return h.Handler.Handle(ctx, r)
+}
+
+func TestMultiHandler_ClonesRecord(t *testing.T) {
+	mem1, mem2, plain := NewMemoryHandler(1), NewMemoryHandler(1), NewMemoryHandler(1)
+	h := NewMultiHandler(
+		&tagHandler{Handler: mem1, name: "a"},
+		&tagHandler{Handler: mem2, name: "b"},
+		plain,
+	)
+
+	// 超过 Record 内联容量的属性存放在共享的切片中，未 Clone 时分支追加的属性会互相覆盖
+	r := slog.NewRecord(time.Now(), slog.LevelInfo, "hello", 0)
+	for i := 0; i < 8; i++ {
+		r.AddAttrs(slog.Int(strconv.Itoa(i), i))
+	}
+	if err := h.Handle(context.Background(), r); err != nil {
+		t.Fatalf("Handle() error = %v", err)
+	}
+
+	for _, tt := range []struct {
+		mem  *MemoryHandler
+		want string
+	}{{mem1, "a"}, {mem2, "b"}} {
+		rec := tt.mem.Records()[0]
+		if v, ok := rec.Attr("branch"); !ok || v.String() != tt.want {
+			t.Errorf("branch = %v, want %q", v, tt.want)
+		}
+		if len(rec.Attrs) != 9 {
+			t.Errorf("len(Attrs) = %d, want 9", len(rec.Attrs))
+		}
+	}
+	if _, ok := plain.Records()[0].Attr("branch"); ok {
+		t.Error("attrs added by one branch should not leak into other branches")
+	}
+	if r.NumAttrs() != 8 {
+		t.Errorf("original record NumAttrs() = %d, want 8", r.NumAttrs())
+	}
+}
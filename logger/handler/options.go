@@ -0,0 +1,161 @@
+package handler
+
+import (
+	"io"
+	"os"
+	"strconv"
+	"time"
+	"unicode/utf8"
+)
+
+const seqKey = "seq"
+
+// TimePrecision 日志时间的输出精度
+type TimePrecision int
+
+const (
+	// TimePrecisionSecond 精确到秒，如 2006-01-02 15:04:05，默认值
+	TimePrecisionSecond TimePrecision = iota
+	// TimePrecisionMillisecond 精确到毫秒，如 2006-01-02 15:04:05.000
+	TimePrecisionMillisecond
+	// TimePrecisionMicrosecond 精确到微秒，如 2006-01-02 15:04:05.000000
+	TimePrecisionMicrosecond
+	// TimePrecisionNanosecond 精确到纳秒，如 2006-01-02 15:04:05.000000000
+	TimePrecisionNanosecond
+)
+
+// TimeFormat 的特殊取值，输出 Unix 时间戳而不是格式化的时间
+const (
+	// TimeFormatUnix 输出秒级 Unix 时间戳，如 1704135845
+	TimeFormatUnix = "unix"
+	// TimeFormatUnixMilli 输出毫秒级 Unix 时间戳，如 1704135845123
+	TimeFormatUnixMilli = "unixmilli"
+)
+
+// ColorMode StdHandler 的颜色输出模式
+type ColorMode int
+
+const (
+	// ColorAlways 总是输出 ANSI 颜色控制码，默认值
+	ColorAlways ColorMode = iota
+	// ColorNever 不输出颜色，适用于输出重定向到文件的场景
+	ColorNever
+	// ColorAuto 当输出目标是终端时才输出颜色
+	ColorAuto
+)
+
+// Options DefaultHandler、StdHandler 和 PrettyHandler 的可选配置，零值即默认行为
+type Options struct {
+	// TimePrecision 日志时间的精度，同一秒内的高频日志可使用更高精度以保证顺序
+	TimePrecision TimePrecision
+
+	// TimeFormat 日志时间及 time.Time 类型属性值的格式，为空时使用 TimePrecision 对应的默认格式
+	// 设置后 TimePrecision 不再生效，如 time.RFC3339Nano；
+	// 也可使用 TimeFormatUnix、TimeFormatUnixMilli 输出 Unix 时间戳
+	TimeFormat string
+
+	// AddSequence 为每条日志添加单调递增的 seq 属性，可用于排查丢日志、乱序的问题
+	// WithAttrs/WithGroup 派生出的 Handler 共享同一个计数器
+	AddSequence bool
+
+	// AddRecordID 为每条日志添加唯一的 log_id 属性（ULID，26 个字符），
+	// 日志以至少一次语义投递时，下游可据此去重；ID 的字典序即生成时间顺序
+	AddRecordID bool
+
+	// CallerSkip 解析 caller 时从日志调用处（slog.Record.PC）向上额外跳过的层级，默认 0 即调用 slog 的位置
+	// 在 logger 外封装了自己的日志辅助函数时，每多一层封装加 1，使 caller 指向真正的调用方
+	// caller、func= 和 stack= 均从跳过后的位置开始，不受 Handler 被包装的层数影响
+	CallerSkip int
+
+	// AddFuncName 在 caller 之后额外输出调用方的函数名，如 func=service.(*User).Login
+	// 需要解析符号信息，默认关闭
+	AddFuncName bool
+
+	// AddStackOnError Error 及以上级别的日志自动在末尾附加 stack= 调用栈，调用栈从日志调用处开始
+	AddStackOnError bool
+
+	// ErrorChain 对包装过的 error 属性（如 fmt.Errorf("...: %w", err)），额外输出 <key>_chain，
+	// 按 errors.Unwrap 从外到内列出每一层的错误信息，如 error_chain=["a: b: c","b: c","c"]
+	ErrorChain bool
+
+	// CollapseAttrs 将所有属性序列化为一个 JSON 对象，以 attrs={...} 的形式输出，
+	// 而不是逐个输出 k=v，便于下游在属性 key 不固定时解析
+	CollapseAttrs bool
+
+	// MaxValueLen 字符串属性值的最大字节数，超出部分会被截断并追加 ...(truncated N bytes)
+	// 用于避免请求体等超长属性产生巨大的日志行，<= 0 表示不限制
+	MaxValueLen int
+
+	// Color 颜色输出模式，对 StdHandler 和 PrettyHandler 生效
+	Color ColorMode
+}
+
+// formatTime 按 TimeFormat 或 TimePrecision 格式化日志时间
+func (o *Options) formatTime(t time.Time) string {
+	switch o.TimeFormat {
+	case "":
+		return t.Format(o.timeLayout())
+	case TimeFormatUnix:
+		return strconv.FormatInt(t.Unix(), 10)
+	case TimeFormatUnixMilli:
+		return strconv.FormatInt(t.UnixMilli(), 10)
+	default:
+		return t.Format(o.TimeFormat)
+	}
+}
+
+// timeLayout 返回日志时间的格式
+func (o *Options) timeLayout() string {
+	switch o.TimePrecision {
+	case TimePrecisionMillisecond:
+		return "2006-01-02 15:04:05.000"
+	case TimePrecisionMicrosecond:
+		return "2006-01-02 15:04:05.000000"
+	case TimePrecisionNanosecond:
+		return "2006-01-02 15:04:05.000000000"
+	default:
+		return "2006-01-02 15:04:05"
+	}
+}
+
+// callerSkip 返回从日志调用处向上跳过的层级
+func (o *Options) callerSkip() int {
+	return max(o.CallerSkip, 0)
+}
+
+// truncate 按 MaxValueLen 截断字符串属性值，不会拆开多字节字符
+func (o *Options) truncate(s string) string {
+	if o.MaxValueLen <= 0 || len(s) <= o.MaxValueLen {
+		return s
+	}
+	n := o.MaxValueLen
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n] + "...(truncated " + strconv.Itoa(len(s)-n) + " bytes)"
+}
+
+// useColor 判断是否需要向 w 输出颜色
+func (o *Options) useColor(w io.Writer) bool {
+	switch o.Color {
+	case ColorNever:
+		return false
+	case ColorAuto:
+		return isTerminal(w)
+	default:
+		return true
+	}
+}
+
+// isTerminal 判断 w 是否为终端（字符设备）
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
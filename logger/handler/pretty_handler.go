@@ -0,0 +1,225 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// prettyIndent 每一层缩进的空格
+const prettyIndent = "  "
+
+// PrettyHandler 多行缩进输出的 Handler，便于本地开发时阅读属性较多的日志
+//
+//	INFO: 2024-01-02 03:04:05 logger/foo.go:12
+//	  msg: hello
+//	  user: tom
+//	  req:
+//	    path: /v1
+type PrettyHandler struct {
+	w     io.Writer
+	level slog.Level
+	state groupState
+	opts  Options
+	seq   *atomic.Uint64
+	color bool
+	mu    sync.Mutex
+}
+
+// NewPrettyHandler 创建多行缩进输出的 Handler
+func NewPrettyHandler(w io.Writer, level slog.Level) *PrettyHandler {
+	return NewPrettyHandlerWithOptions(w, level, nil)
+}
+
+// NewPrettyHandlerWithOptions 创建多行缩进输出的 Handler，opts 为 nil 时使用默认配置
+func NewPrettyHandlerWithOptions(w io.Writer, level slog.Level, opts *Options) *PrettyHandler {
+	h := &PrettyHandler{
+		w:     w,
+		level: level,
+		seq:   new(atomic.Uint64),
+	}
+	if opts != nil {
+		h.opts = *opts
+	}
+	h.color = h.opts.useColor(w)
+	return h
+}
+
+func (h *PrettyHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level
+}
+
+func (h *PrettyHandler) Handle(ctx context.Context, r slog.Record) error {
+	buf := getBuffer()
+	defer putBuffer(buf)
+
+	// 首行：级别、时间、caller
+	h.writeColor(buf, levelColor(r.Level))
+	buf.WriteString(r.Level.String())
+	h.writeColor(buf, colorReset)
+	buf.WriteString(": ")
+
+	h.writeColor(buf, colorGray)
+	buf.WriteString(h.opts.formatTime(r.Time))
+	h.writeColor(buf, colorReset)
+
+	frame, hasCaller := callerFrame(r.PC, h.opts.callerSkip())
+	if hasCaller {
+		buf.WriteByte(' ')
+		h.writeColor(buf, colorCyan)
+		writeFrame(buf, frame)
+		h.writeColor(buf, colorReset)
+	}
+	buf.WriteByte('\n')
+
+	// 其余内容每项一行
+	if h.opts.AddFuncName && frame.Function != "" {
+		h.writeLine(buf, 1, funcKey, shortFuncName(frame.Function))
+	}
+	if traceID, ok := TraceIDFromContext(ctx); ok {
+		h.writeLine(buf, 1, traceIDKey, traceID)
+	}
+	h.writeLine(buf, 1, "msg", r.Message)
+	if h.opts.AddSequence {
+		h.writeLine(buf, 1, seqKey, strconv.FormatUint(h.seq.Add(1), 10))
+	}
+	if h.opts.AddRecordID {
+		h.writeLine(buf, 1, recordIDKey, recordIDs.next())
+	}
+
+	for _, ga := range h.state.attrs {
+		h.writeAttr(buf, ga.prefix, ga.attr)
+	}
+	r.Attrs(func(attr slog.Attr) bool {
+		h.writeAttr(buf, h.state.prefix, attr)
+		return true
+	})
+
+	// Error 及以上级别自动附加调用栈，与 caller 指向同一层
+	if h.opts.AddStackOnError && r.Level >= slog.LevelError && r.PC != 0 {
+		h.writeLine(buf, 1, stackKey, stackFromPC(r.PC, h.opts.callerSkip()))
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := h.w.Write(buf.Bytes())
+	return err
+}
+
+// writeAttr 写入一个顶层属性，key 加上 WithGroup 的分组前缀 prefix
+// 开启 ErrorChain 时在 error 属性之后写入错误链
+func (h *PrettyHandler) writeAttr(buf *bytes.Buffer, prefix string, attr slog.Attr) {
+	attr.Value = attr.Value.Resolve()
+	if attr.Key == "" && attr.Value.Kind() == slog.KindGroup {
+		// 空 key 的分组内联到当前分组
+		for _, ga := range attr.Value.Group() {
+			h.writeAttr(buf, prefix, ga)
+		}
+		return
+	}
+
+	h.appendAttr(buf, 1, prefix+attr.Key, attr.Value)
+	if chain, ok := h.opts.errorChainAttr(attr); ok {
+		h.appendAttr(buf, 1, prefix+chain.Key, chain.Value)
+	}
+}
+
+// appendAttr 写入一个属性，分组属性展开为下一层缩进
+func (h *PrettyHandler) appendAttr(buf *bytes.Buffer, depth int, key string, v slog.Value) {
+	v = v.Resolve()
+	if v.Kind() != slog.KindGroup {
+		value := formatValue(v)
+		switch v.Kind() {
+		case slog.KindString:
+			value = h.opts.truncate(value)
+		case slog.KindTime:
+			value = h.opts.formatTime(v.Time())
+		}
+		h.writeLine(buf, depth, key, value)
+		return
+	}
+
+	group := v.Group()
+	if len(group) == 0 {
+		return
+	}
+	if key == "" {
+		// 空 key 的分组内联到当前层级
+		for _, a := range group {
+			h.appendAttr(buf, depth, a.Key, a.Value)
+		}
+		return
+	}
+
+	h.writeIndent(buf, depth)
+	buf.WriteString(key)
+	buf.WriteString(":\n")
+	for _, a := range group {
+		h.appendAttr(buf, depth+1, a.Key, a.Value)
+	}
+}
+
+func (h *PrettyHandler) writeLine(buf *bytes.Buffer, depth int, key, value string) {
+	h.writeIndent(buf, depth)
+	buf.WriteString(key)
+	buf.WriteString(": ")
+	buf.WriteString(value)
+	buf.WriteByte('\n')
+}
+
+func (h *PrettyHandler) writeIndent(buf *bytes.Buffer, depth int) {
+	for i := 0; i < depth; i++ {
+		buf.WriteString(prettyIndent)
+	}
+}
+
+// writeColor 写入颜色控制码，未开启颜色时不写入任何内容
+func (h *PrettyHandler) writeColor(buf *bytes.Buffer, color string) {
+	if h.color {
+		buf.WriteString(color)
+	}
+}
+
+func (h *PrettyHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &PrettyHandler{
+		w:     h.w,
+		level: h.level,
+		state: h.state.withAttrs(attrs),
+		opts:  h.opts,
+		seq:   h.seq,
+		color: h.color,
+	}
+}
+
+func (h *PrettyHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	return &PrettyHandler{
+		w:     h.w,
+		level: h.level,
+		state: h.state.withGroup(name),
+		opts:  h.opts,
+		seq:   h.seq,
+		color: h.color,
+	}
+}
+
+// formatValue 按值类型格式化，格式与 DefaultHandler 保持一致
+func formatValue(v slog.Value) string {
+	switch v.Kind() {
+	case slog.KindString:
+		return v.String()
+	case slog.KindTime:
+		return v.Time().Format(time.DateTime)
+	default:
+		return fmt.Sprint(v.Any())
+	}
+}
@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Twelveeee/golib/constant"
+)
+
+func TestPrettyHandler(t *testing.T) {
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 0, time.Local)
+
+	var out bytes.Buffer
+	h := NewPrettyHandlerWithOptions(&out, slog.LevelInfo, &Options{Color: ColorNever})
+	r := slog.NewRecord(ts, slog.LevelInfo, "hello", 0)
+	r.AddAttrs(
+		slog.String("user", "tom"),
+		slog.Int("count", 3),
+		slog.Group("req", slog.String("path", "/v1"), slog.Duration("cost", time.Second)),
+	)
+	ctx := context.WithValue(context.Background(), constant.TraceIDKey, "abc")
+	if err := h.WithAttrs([]slog.Attr{slog.String("service", "api")}).Handle(ctx, r); err != nil {
+		t.Fatalf("Handle() error = %v", err)
+	}
+
+	want := strings.Join([]string{
+		"INFO: 2024-01-02 03:04:05",
+		"  traceID: abc",
+		"  msg: hello",
+		"  service: api",
+		"  user: tom",
+		"  count: 3",
+		"  req:",
+		"    path: /v1",
+		"    cost: 1s",
+		"",
+	}, "\n")
+	if out.String() != want {
+		t.Errorf("output =\n%s\nwant\n%s", out.String(), want)
+	}
+}
+
+func TestPrettyHandler_Color(t *testing.T) {
+	var out bytes.Buffer
+	h := NewPrettyHandler(&out, slog.LevelInfo)
+	if err := h.Handle(context.Background(), slog.NewRecord(time.Now(), slog.LevelWarn, "hello", 0)); err != nil {
+		t.Fatalf("Handle() error = %v", err)
+	}
+	if !strings.HasPrefix(out.String(), colorYellow+"WARN"+colorReset) {
+		t.Errorf("output = %q, want colored level", out.String())
+	}
+}
+
+func TestPrettyHandler_ErrorChain(t *testing.T) {
+	var out bytes.Buffer
+	err := fmt.Errorf("load user: %w", fmt.Errorf("query db: %w", errors.New("timeout")))
+	slog.New(NewPrettyHandlerWithOptions(&out, slog.LevelInfo, &Options{ErrorChain: true})).Error("failed", "err", err)
+
+	want := "  err: load user: query db: timeout\n  err_chain: [\"load user: query db: timeout\",\"query db: timeout\",\"timeout\"]\n"
+	if !strings.Contains(out.String(), want) {
+		t.Errorf("output = %q, want %q", out.String(), want)
+	}
+}
@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+const recordIDKey = "log_id"
+
+// crockfordAlphabet ULID 使用的 Crockford Base32 字符集，不含 I、L、O、U
+const crockfordAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// recordIDs 所有 Handler 共享的 ID 生成器，保证同一进程内生成的 ID 严格递增
+var recordIDs = &ulidGenerator{}
+
+// ulidGenerator 生成单调递增的 ULID
+// ULID 由 48 位毫秒时间戳和 80 位随机数组成，编码为 26 个字符，字典序即时间顺序；
+// 同一毫秒内（或时钟回拨时）沿用上一个时间戳并将随机部分加 1，保证严格递增
+type ulidGenerator struct {
+	mu     sync.Mutex
+	lastMs uint64
+	randHi uint16 // 随机部分的高 16 位
+	randLo uint64 // 随机部分的低 64 位
+}
+
+// next 生成下一个 ULID
+func (g *ulidGenerator) next() string {
+	ms := uint64(time.Now().UnixMilli())
+
+	g.mu.Lock()
+	if ms > g.lastMs {
+		g.lastMs = ms
+		g.randHi = uint16(rand.Uint32())
+		g.randLo = rand.Uint64()
+	} else {
+		g.randLo++
+		if g.randLo == 0 {
+			g.randHi++
+			if g.randHi == 0 {
+				// 随机部分溢出，借用下一毫秒
+				g.lastMs++
+			}
+		}
+	}
+	ms, hi, lo := g.lastMs, g.randHi, g.randLo
+	g.mu.Unlock()
+
+	return encodeULID(ms, hi, lo)
+}
+
+// encodeULID 将 128 位的 ULID 编码为 26 个 Crockford Base32 字符
+func encodeULID(ms uint64, randHi uint16, randLo uint64) string {
+	// 128 位拆分为高低两个 uint64：时间戳 48 位 + 随机数高 16 位，随机数低 64 位
+	hi := ms<<16 | uint64(randHi)
+	lo := randLo
+
+	var out [26]byte
+	for i := len(out) - 1; i >= 0; i-- {
+		out[i] = crockfordAlphabet[lo&0x1f]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(out[:])
+}
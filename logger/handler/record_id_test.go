@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"log/slog"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDefaultHandler_AddRecordID(t *testing.T) {
+	var out lockedBuffer
+	logger := slog.New(NewDefaultHandlerWithOptions(&out, slog.LevelInfo, &Options{AddRecordID: true}))
+
+	const (
+		goroutines = 10
+		iterations = 100
+	)
+	var wg sync.WaitGroup
+	wg.Add(goroutines)
+	for i := 0; i < goroutines; i++ {
+		go func() {
+			defer wg.Done()
+			for j := 0; j < iterations; j++ {
+				logger.Info("id test")
+			}
+		}()
+	}
+	wg.Wait()
+
+	idReg := regexp.MustCompile(` log_id=(\S+)`)
+	ulidReg := regexp.MustCompile(`^[0-9A-HJKMNP-TV-Z]{26}$`)
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != goroutines*iterations {
+		t.Fatalf("got %d lines, want %d", len(lines), goroutines*iterations)
+	}
+	seen := make(map[string]bool, len(lines))
+	for _, line := range lines {
+		m := idReg.FindStringSubmatch(line)
+		if m == nil {
+			t.Fatalf("log_id not found in %q", line)
+		}
+		if !ulidReg.MatchString(m[1]) {
+			t.Errorf("log_id %q is not a well-formed ULID", m[1])
+		}
+		if seen[m[1]] {
+			t.Errorf("duplicate log_id %q", m[1])
+		}
+		seen[m[1]] = true
+	}
+}
+
+func TestULIDGenerator_Sortable(t *testing.T) {
+	g := &ulidGenerator{}
+	ids := make([]string, 0, 200)
+	for i := 0; i < 100; i++ {
+		ids = append(ids, g.next())
+	}
+	// 跨毫秒生成的 ID 同样按时间排序
+	time.Sleep(2 * time.Millisecond)
+	for i := 0; i < 100; i++ {
+		ids = append(ids, g.next())
+	}
+
+	if !slices.IsSorted(ids) {
+		t.Error("ULIDs should be sorted by generation time")
+	}
+	if len(slices.Compact(slices.Clone(ids))) != len(ids) {
+		t.Error("ULIDs should be unique")
+	}
+}
+
+func TestEncodeULID(t *testing.T) {
+	// 时间戳部分为前 10 个字符
+	ms := uint64(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC).UnixMilli())
+	got := encodeULID(ms, 0, 0)
+	if got != "01HK421P480000000000000000" {
+		t.Errorf("encodeULID() = %q", got)
+	}
+	if largest := encodeULID(1<<48-1, 0xffff, 1<<64-1); largest != "7ZZZZZZZZZZZZZZZZZZZZZZZZZ" {
+		t.Errorf("encodeULID(max) = %q, want 7ZZZZZZZZZZZZZZZZZZZZZZZZZ", largest)
+	}
+}
@@ -0,0 +1,151 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/Twelveeee/golib/constant"
+)
+
+// SamplingOptions 采样 Handler 的配置
+type SamplingOptions struct {
+	// Rate 保留比例，>=1 表示全部保留，<=0 表示全部丢弃
+	Rate float64
+
+	// MaxPerSecond 每秒最多保留的日志条数，<=0 表示不限制
+	MaxPerSecond int
+
+	// FailOpenWindow 大于 0 时开启 fail open：每个窗口内每种 msg 的第一条日志总是保留，
+	// 不受 Rate 和 MaxPerSecond 限制，只对之后重复出现的日志采样，避免少见但重要的日志被采样掉
+	// 窗口内会记录所有出现过的 msg，msg 中不应包含请求 ID 等高基数内容
+	FailOpenWindow time.Duration
+}
+
+// SamplingHandler 按比例采样日志的 Handler，被采样保留的日志交给 inner 处理
+//
+// 若 context 中已通过 logger.WithSampleDecision 设置了采样决策，则直接使用该决策，
+// 这样同一个请求经过多个 SamplingHandler 时，会得到一致的保留/丢弃结果
+type SamplingHandler struct {
+	inner slog.Handler
+	opts  SamplingOptions
+	state *samplingState // WithAttrs/WithGroup 派生出的 Handler 共享同一个计数状态
+}
+
+// samplingState 每秒上限和 fail open 窗口的计数状态
+type samplingState struct {
+	mu          sync.Mutex
+	second      int64 // 当前计数的秒
+	secondCount int
+	windowStart time.Time
+	seen        map[string]struct{}
+}
+
+// NewSamplingHandler 创建采样 Handler
+// rate 为保留比例，>=1 表示全部保留，<=0 表示全部丢弃
+func NewSamplingHandler(inner slog.Handler, rate float64) *SamplingHandler {
+	return NewSamplingHandlerWithOptions(inner, &SamplingOptions{Rate: rate})
+}
+
+// NewSamplingHandlerWithOptions 按配置创建采样 Handler，opts 为 nil 时全部丢弃
+func NewSamplingHandlerWithOptions(inner slog.Handler, opts *SamplingOptions) *SamplingHandler {
+	h := &SamplingHandler{
+		inner: inner,
+		state: &samplingState{seen: make(map[string]struct{})},
+	}
+	if opts != nil {
+		h.opts = *opts
+	}
+	return h
+}
+
+func (h *SamplingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.inner.Enabled(ctx, level)
+}
+
+func (h *SamplingHandler) Handle(ctx context.Context, r slog.Record) error {
+	if !h.keep(ctx, r) {
+		return nil
+	}
+	return h.inner.Handle(ctx, r)
+}
+
+// keep 判断当前日志是否保留
+func (h *SamplingHandler) keep(ctx context.Context, r slog.Record) bool {
+	if keep, ok := SampleDecisionFromContext(ctx); ok {
+		return keep
+	}
+	if h.opts.MaxPerSecond <= 0 && h.opts.FailOpenWindow <= 0 {
+		return h.sample()
+	}
+
+	now := time.Now()
+	s := h.state
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if h.opts.FailOpenWindow > 0 {
+		if now.Sub(s.windowStart) >= h.opts.FailOpenWindow {
+			s.windowStart = now
+			clear(s.seen)
+		}
+		if _, ok := s.seen[r.Message]; !ok {
+			s.seen[r.Message] = struct{}{}
+			return true
+		}
+	}
+
+	if h.opts.MaxPerSecond > 0 {
+		if sec := now.Unix(); sec != s.second {
+			s.second = sec
+			s.secondCount = 0
+		}
+		if s.secondCount >= h.opts.MaxPerSecond {
+			return false
+		}
+	}
+
+	if !h.sample() {
+		return false
+	}
+	s.secondCount++
+	return true
+}
+
+// sample 按 Rate 随机采样
+func (h *SamplingHandler) sample() bool {
+	if h.opts.Rate >= 1 {
+		return true
+	}
+	if h.opts.Rate <= 0 {
+		return false
+	}
+	return rand.Float64() < h.opts.Rate
+}
+
+func (h *SamplingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &SamplingHandler{
+		inner: h.inner.WithAttrs(attrs),
+		opts:  h.opts,
+		state: h.state,
+	}
+}
+
+func (h *SamplingHandler) WithGroup(name string) slog.Handler {
+	return &SamplingHandler{
+		inner: h.inner.WithGroup(name),
+		opts:  h.opts,
+		state: h.state,
+	}
+}
+
+// SampleDecisionFromContext 读取 context 中的采样决策
+func SampleDecisionFromContext(ctx context.Context) (keep bool, ok bool) {
+	if ctx == nil {
+		return false, false
+	}
+	keep, ok = ctx.Value(constant.SampleDecisionKey).(bool)
+	return keep, ok
+}
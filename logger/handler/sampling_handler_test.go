@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Twelveeee/golib/constant"
+)
+
+func TestSamplingHandler(t *testing.T) {
+	tests := []struct {
+		name string
+		rate float64
+		ctx  context.Context
+		want int64
+	}{
+		{name: "rate 1 keeps all", rate: 1, ctx: context.Background(), want: 10},
+		{name: "rate 0 drops all", rate: 0, ctx: context.Background(), want: 0},
+		{name: "context keep overrides rate", rate: 0, ctx: context.WithValue(context.Background(), constant.SampleDecisionKey, true), want: 10},
+		{name: "context drop overrides rate", rate: 1, ctx: context.WithValue(context.Background(), constant.SampleDecisionKey, false), want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			counter := NewCountingHandler(NewDefaultHandler(discardWriter{}, slog.LevelInfo))
+			logger := slog.New(NewSamplingHandler(counter, tt.rate))
+			for i := 0; i < 10; i++ {
+				logger.InfoContext(tt.ctx, "sampled")
+			}
+			if got := counter.Counts()[slog.LevelInfo]; got != tt.want {
+				t.Errorf("kept %d records, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSamplingHandler_FailOpen(t *testing.T) {
+	var out bytes.Buffer
+	h := NewSamplingHandlerWithOptions(NewDefaultHandler(&out, slog.LevelInfo), &SamplingOptions{
+		Rate:           1,
+		MaxPerSecond:   5,
+		FailOpenWindow: time.Minute,
+	})
+	logger := slog.New(h)
+
+	// 其他日志远超每秒上限
+	for i := 0; i < 100; i++ {
+		logger.Info("noisy")
+	}
+	noisy := strings.Count(out.String(), "msg=noisy")
+	// 首条 fail open 放行，其余受每秒上限限制；跨秒时最多再放行一轮
+	if noisy < 6 || noisy > 11 {
+		t.Errorf("noisy kept %d records, want limited by per-second cap", noisy)
+	}
+
+	// 上限已被占满，窗口内首次出现的日志仍然放行，派生的 Handler 共享同一窗口
+	logger.With("k", "v").Info("rare")
+	if got := strings.Count(out.String(), "msg=rare"); got != 1 {
+		t.Errorf("first occurrence of rare kept %d records, want 1", got)
+	}
+}
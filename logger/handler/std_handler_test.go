@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStdHandler_TimePrecision(t *testing.T) {
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 123456789, time.Local)
+
+	var out bytes.Buffer
+	h := NewStdHandlerWithOptions(&out, slog.LevelInfo, &Options{TimePrecision: TimePrecisionMillisecond})
+	if err := h.Handle(context.Background(), slog.NewRecord(ts, slog.LevelInfo, "hello", 0)); err != nil {
+		t.Fatalf("Handle() error = %v", err)
+	}
+
+	want := colorGray + "2024-01-02 03:04:05.123" + colorReset
+	if !strings.Contains(out.String(), want) {
+		t.Errorf("output = %q, want contains %q", out.String(), want)
+	}
+}
+
+func TestStdHandler_ColorMode(t *testing.T) {
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 0, time.Local)
+	newRecord := func() slog.Record {
+		r := slog.NewRecord(ts, slog.LevelWarn, "hello", 0)
+		r.AddAttrs(slog.String("k", "v"), slog.Int("n", 1))
+		return r
+	}
+	render := func(mode ColorMode) string {
+		var out bytes.Buffer
+		h := NewStdHandlerWithOptions(&out, slog.LevelInfo, &Options{Color: mode})
+		if err := h.WithAttrs([]slog.Attr{slog.String("pre", "x")}).Handle(context.Background(), newRecord()); err != nil {
+			t.Fatalf("Handle() error = %v", err)
+		}
+		return out.String()
+	}
+
+	colored := render(ColorAlways)
+	if !strings.Contains(colored, "\033[") {
+		t.Fatalf("ColorAlways output should contain ANSI escapes: %q", colored)
+	}
+
+	for _, mode := range []ColorMode{ColorNever, ColorAuto} {
+		plain := render(mode)
+		if strings.Contains(plain, "\033[") {
+			t.Errorf("mode %d output should not contain ANSI escapes: %q", mode, plain)
+		}
+
+		// 去掉颜色控制码后应与无颜色输出完全一致
+		stripped := colored
+		for _, c := range []string{colorReset, colorRed, colorYellow, colorBlue, colorGray, colorCyan} {
+			stripped = strings.ReplaceAll(stripped, c, "")
+		}
+		if stripped != plain {
+			t.Errorf("mode %d output = %q, want %q", mode, plain, stripped)
+		}
+	}
+}
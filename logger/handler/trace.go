@@ -0,0 +1,21 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/Twelveeee/golib/constant"
+)
+
+const traceIDKey = "traceID"
+
+// TraceIDFromContext 读取 context 中的 traceID，不存在或为空时 ok 为 false
+func TraceIDFromContext(ctx context.Context) (traceID string, ok bool) {
+	if ctx == nil {
+		return "", false
+	}
+	traceID, ok = ctx.Value(constant.TraceIDKey).(string)
+	if !ok || traceID == "" {
+		return "", false
+	}
+	return traceID, true
+}
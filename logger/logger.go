@@ -14,10 +14,13 @@ import (
 	"github.com/Twelveeee/golib/logger/writer"
 )
 
-func NewLogger(ctx context.Context, conf *Config) (l *slog.Logger, closeFunc func() error, errResult error) {
+// NewLogger 创建写文件的日志
+// closeFunc 将缓冲中的日志落盘并关闭文件，可重复调用；ctx 不为 nil 时在 ctx 结束后自动调用
+// flushFunc 将调用前写入的日志落盘但不关闭，可在容易崩溃的操作前调用；可并发调用，关闭后调用为空操作
+func NewLogger(ctx context.Context, conf *Config) (l *slog.Logger, closeFunc func() error, flushFunc func() error, errResult error) {
 	// 验证和设置默认值
 	if err := conf.Validate(); err != nil {
-		return nil, nil, fmt.Errorf("invalid config: %w", err)
+		return nil, nil, nil, fmt.Errorf("invalid config: %w", err)
 	}
 	conf.SetDefaults()
 
@@ -42,10 +45,15 @@ func NewLogger(ctx context.Context, conf *Config) (l *slog.Logger, closeFunc fun
 
 	writer, err := conf.getWriter()
 	if err != nil {
-		return nil, nil, fmt.Errorf("init logger (%q) failed: %w", conf.FileName, err)
+		return nil, nil, nil, fmt.Errorf("init logger (%q) failed: %w", conf.FileName, err)
 	}
 
-	closeFns = append(closeFns, writer.Close)
+	closeFns = append(closeFns, func() error {
+		return closeWriter(writer, conf.CloseTimeout)
+	})
+	flushFunc = func() error {
+		return flushWriter(writer)
+	}
 
 	// 如果是 Debug 级别，同时输出到标准输出
 	var logHandler slog.Handler
@@ -57,7 +65,16 @@ func NewLogger(ctx context.Context, conf *Config) (l *slog.Logger, closeFunc fun
 		logHandler = handler.NewDefaultHandler(writer, conf.Level)
 	}
 
+	if len(conf.DefaultAttrs) > 0 {
+		logHandler = logHandler.WithAttrs(conf.DefaultAttrs)
+	}
+
 	l = slog.New(logHandler)
+	if conf.AddVersion {
+		if v := Version(); v != "" {
+			l = l.With(versionKey, v)
+		}
+	}
 
 	if ctx != nil {
 		go func() {
@@ -68,7 +85,32 @@ func NewLogger(ctx context.Context, conf *Config) (l *slog.Logger, closeFunc fun
 		}()
 	}
 
-	return l, closeWritersFunc, nil
+	return l, closeWritersFunc, flushFunc, nil
+}
+
+// NewConsoleLogger 创建只输出到标准输出的带颜色日志，不写文件，无需 Config 与 closeFunc，适用于命令行工具
+func NewConsoleLogger(level slog.Level) *slog.Logger {
+	return slog.New(handler.NewStdHandler(os.Stdout, level))
+}
+
+// closeWriter 关闭 writer，closeTimeout > 0 且 writer 支持 writer.ContextCloser 时最多等待 closeTimeout 毫秒，
+// 超时后丢弃未写完的日志，返回的错误中包含丢弃条数
+func closeWriter(w io.WriteCloser, closeTimeout int) error {
+	cc, ok := w.(writer.ContextCloser)
+	if closeTimeout <= 0 || !ok {
+		return w.Close()
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(closeTimeout)*time.Millisecond)
+	defer cancel()
+	return cc.CloseContext(ctx)
+}
+
+// flushWriter 刷新 writer，未实现 writer.Flusher 时为空操作
+func flushWriter(w io.Writer) error {
+	if f, ok := w.(writer.Flusher); ok {
+		return f.Flush()
+	}
+	return nil
 }
 
 func (conf *Config) getWriter() (io.WriteCloser, error) {
@@ -76,7 +118,7 @@ func (conf *Config) getWriter() (io.WriteCloser, error) {
 		return conf.writer, nil
 	}
 	// 以下内容是创建一个writer所需要的配置
-	rp, err := writer.NewSimpleRotateProducer(conf.RotateRule, conf.FileName)
+	rp, err := writer.NewSimpleRotateProducerInLocation(conf.RotateRule, conf.FileName, conf.Location)
 	if err != nil {
 		return nil, err
 	}
@@ -86,6 +128,9 @@ func (conf *Config) getWriter() (io.WriteCloser, error) {
 		FlushDuration: time.Duration(conf.FlushDuration) * time.Millisecond,
 		CheckDuration: 1 * time.Second,
 		MaxFileNum:    conf.MaxFileNum,
+		SyncOnFlush:   conf.SyncOnFlush,
+		// 写入和落盘错误均由 rotate writer 上报，async writer 无需重复上报
+		OnError: conf.OnWriteError,
 	}
 
 	w, errRw := writer.NewRotate(writerOption)
@@ -0,0 +1,181 @@
+package logger
+
+import (
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Twelveeee/golib/logger/writer"
+)
+
+func TestNewLogger_DebugCaller(t *testing.T) {
+	// Debug 级别下同时输出到标准输出，替换 os.Stdout 以捕获输出
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe: %v", err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	l, file := newTestLogger(t, &Config{Level: slog.LevelDebug})
+	os.Stdout = stdout
+
+	_, _, line, _ := runtime.Caller(0)
+	l.Info("hello") // 与上一行相邻
+	_ = w.Close()
+	stdoutOut, _ := io.ReadAll(r)
+
+	// 经过 MultiHandler 包装后，文件和标准输出的 caller 都指向日志调用处
+	want := "logger/logger_test.go:" + strconv.Itoa(line+1)
+	if !strings.Contains(file.String(), want+" ") {
+		t.Errorf("file output = %q, want caller %q", file.String(), want)
+	}
+	if !strings.Contains(string(stdoutOut), want) {
+		t.Errorf("stdout output = %q, want caller %q", stdoutOut, want)
+	}
+}
+
+func TestNewConsoleLogger(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe: %v", err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	l := NewConsoleLogger(slog.LevelInfo)
+	os.Stdout = stdout
+
+	l.Debug("hidden")
+	l.Warn("disk almost full", "usage", 91)
+	_ = w.Close()
+	out, _ := io.ReadAll(r)
+
+	got := string(out)
+	if strings.Contains(got, "hidden") {
+		t.Errorf("output = %q, debug should be filtered", got)
+	}
+	// 级别为黄色，时间为灰色，caller 为青色
+	if !strings.HasPrefix(got, "\033[33mWARN\033[0m: \033[90m") {
+		t.Errorf("output = %q, want colored level and time", got)
+	}
+	if !regexp.MustCompile(`\033\[36m\S*logger/logger_test\.go:\d+\033\[0m `).MatchString(got) || !strings.HasSuffix(got, "msg=disk almost full usage=91\n") {
+		t.Errorf("output = %q, want colored caller followed by message and attrs", got)
+	}
+}
+
+func TestNewLogger_DefaultAttrs(t *testing.T) {
+	l, w := newTestLogger(t, &Config{
+		Level:        slog.LevelInfo,
+		DefaultAttrs: []slog.Attr{slog.String("service", "payments"), slog.String("version", "1.2.3")},
+	})
+
+	l.Info("started")
+	if got := w.String(); !strings.HasSuffix(got, "msg=started service=payments version=1.2.3\n") {
+		t.Errorf("output = %q, want default attrs on an attr-less line", got)
+	}
+
+	w.Reset()
+	l.Info("paid", "order", 42)
+	if got := w.String(); !strings.HasSuffix(got, "msg=paid service=payments version=1.2.3 order=42\n") {
+		t.Errorf("output = %q, want default attrs before record attrs", got)
+	}
+}
+
+// slowWriteCloser 每次写入耗时 delay 的 writer，模拟落盘慢
+type slowWriteCloser struct {
+	delay time.Duration
+}
+
+func (w slowWriteCloser) Write(p []byte) (int, error) {
+	time.Sleep(w.delay)
+	return len(p), nil
+}
+
+func (slowWriteCloser) Close() error { return nil }
+
+func TestNewLogger_CloseTimeout(t *testing.T) {
+	conf := &Config{
+		FileName:     "test.log",
+		Level:        slog.LevelInfo,
+		CloseTimeout: 50,
+	}
+	conf.writer = writer.NewAsync(1000, 0, slowWriteCloser{delay: 10 * time.Millisecond})
+	l, closeFn, _, err := NewLogger(nil, conf)
+	if err != nil {
+		t.Fatalf("NewLogger: %v", err)
+	}
+
+	// 缓冲中的日志全部写完约需 10s
+	for i := 0; i < 1000; i++ {
+		l.Info("hello", "i", i)
+	}
+
+	start := time.Now()
+	err = closeFn()
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("closeFunc took %v, want about 50ms", elapsed)
+	}
+	if err == nil || !strings.Contains(err.Error(), "records dropped") {
+		t.Errorf("closeFunc() error = %v, want dropped records reported", err)
+	}
+}
+
+func TestNewLogger_Flush(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "app.log")
+	l, closeFn, flushFn, err := NewLogger(nil, &Config{
+		FileName:      fileName,
+		RotateRule:    "no",
+		Level:         slog.LevelInfo,
+		FlushDuration: int(time.Hour / time.Millisecond), // 避免定期刷新干扰
+	})
+	if err != nil {
+		t.Fatalf("NewLogger: %v", err)
+	}
+
+	l.Info("before crash-prone operation")
+	if err := flushFn(); err != nil {
+		t.Fatalf("flushFunc() error = %v", err)
+	}
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if !strings.Contains(string(data), "msg=before crash-prone operation") {
+		t.Errorf("file content = %q, want the line on disk before close", data)
+	}
+
+	// 与写入并发调用
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			l.Info("concurrent")
+			if err := flushFn(); err != nil {
+				t.Errorf("flushFunc() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if err := closeFn(); err != nil {
+		t.Fatalf("closeFunc() error = %v", err)
+	}
+	// 关闭后调用为空操作
+	if err := flushFn(); err != nil {
+		t.Errorf("flushFunc() after close error = %v", err)
+	}
+	data, _ = os.ReadFile(fileName)
+	if n := strings.Count(string(data), "msg=concurrent"); n != 8 {
+		t.Errorf("concurrent lines = %d, want 8", n)
+	}
+}
@@ -0,0 +1,28 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"time"
+)
+
+// LogAt 使用调用方指定的时间 t 记录日志，而不是 time.Now()
+// 适用于回放历史事件等需要保留事件原始时间的场景
+func LogAt(l *slog.Logger, t time.Time, level slog.Level, msg string, attrs ...slog.Attr) {
+	logAt(context.Background(), l, t, level, msg, attrs...)
+}
+
+// logAt 构造指定时间的 Record 并交给 l 的 Handler 处理
+// 调用层级与 slog.Logger 的 Info 等方法保持一致，保证 handler 解析出的 caller 是 LogAt 的调用方
+func logAt(ctx context.Context, l *slog.Logger, t time.Time, level slog.Level, msg string, attrs ...slog.Attr) {
+	if !l.Enabled(ctx, level) {
+		return
+	}
+	var pcs [1]uintptr
+	// 跳过 runtime.Callers、logAt、LogAt
+	runtime.Callers(3, pcs[:])
+	r := slog.NewRecord(t, level, msg, pcs[0])
+	r.AddAttrs(attrs...)
+	_ = l.Handler().Handle(ctx, r)
+}
@@ -0,0 +1,55 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Twelveeee/golib/logger/handler"
+)
+
+func TestLogAt(t *testing.T) {
+	var out bytes.Buffer
+	l := slog.New(handler.NewDefaultHandlerWithOptions(&out, slog.LevelInfo, &handler.Options{
+		TimePrecision: handler.TimePrecisionMillisecond,
+	}))
+
+	at := time.Date(2020, 1, 2, 3, 4, 5, 678_000_000, time.Local)
+	_, _, callLine, _ := runtime.Caller(0)
+	LogAt(l, at, slog.LevelInfo, "replay", slog.String("event", "created")) // 与上一行相邻
+
+	line := out.String()
+	if !strings.HasPrefix(line, "INFO: 2020-01-02 03:04:05.678 ") {
+		t.Errorf("output = %q, want the supplied time", line)
+	}
+	if !strings.Contains(line, "event=created") {
+		t.Errorf("output = %q, want attrs", line)
+	}
+	if want := "logger/record_test.go:" + strconv.Itoa(callLine+1) + " "; !strings.Contains(line, want) {
+		t.Errorf("output = %q, want caller %q", line, want)
+	}
+
+	out.Reset()
+	LogAt(l, at, slog.LevelDebug, "dropped")
+	if out.Len() != 0 {
+		t.Errorf("disabled level should not be logged: %q", out.String())
+	}
+}
+
+func TestGormAdapter_NowFunc(t *testing.T) {
+	var out bytes.Buffer
+	l := slog.New(handler.NewDefaultHandler(&out, slog.LevelInfo))
+
+	at := time.Date(2020, 1, 2, 3, 4, 5, 0, time.Local)
+	adapter := NewGormAdapter(l, WithGormNowFunc(func() time.Time { return at }))
+	adapter.Info(context.Background(), "hello %s", "gorm")
+
+	if !strings.HasPrefix(out.String(), "INFO: 2020-01-02 03:04:05 ") {
+		t.Errorf("output = %q, want the time from nowFunc", out.String())
+	}
+}
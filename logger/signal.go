@@ -0,0 +1,57 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// FlushOnSignal 在收到 sigs（默认 SIGINT、SIGTERM）时调用 closeFunc 将缓冲中的日志落盘并关闭 logger，
+// 避免进程被 Ctrl+C 或 kill 终止时丢失最后的日志；closeFunc 通常为 NewLogger 返回的 closeFunc
+//
+// 关闭完成后会停止监听并将信号重新发送给当前进程，由此前的处理方式继续处理：
+// 没有其他监听者时按默认行为退出进程；业务自己通过 signal.Notify 监听了该信号时，
+// 业务的 channel 同样能收到信号（可能收到两次，处理逻辑需可重入），信号不会被吞掉
+// 注意业务收到信号后 logger 可能已关闭，此后写入的日志会丢失
+//
+// 返回的 stop 用于取消监听，可重复调用
+func FlushOnSignal(closeFunc func() error, sigs ...os.Signal) (stop func()) {
+	if len(sigs) == 0 {
+		sigs = []os.Signal{os.Interrupt, syscall.SIGTERM}
+	}
+
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, sigs...)
+
+	done := make(chan struct{})
+	go func() {
+		select {
+		case sig := <-ch:
+			signal.Stop(ch)
+			if err := closeFunc(); err != nil {
+				fmt.Fprintf(os.Stderr, "%s logger close on signal %v error: %v\n", time.Now(), sig, err)
+			}
+			raiseSignal(sig)
+		case <-done:
+			signal.Stop(ch)
+		}
+	}()
+
+	return sync.OnceFunc(func() {
+		close(done)
+	})
+}
+
+// raiseSignal 将信号重新发送给当前进程，平台不支持发送该信号时直接退出进程
+func raiseSignal(sig os.Signal) {
+	p, err := os.FindProcess(os.Getpid())
+	if err == nil {
+		err = p.Signal(sig)
+	}
+	if err != nil {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,90 @@
+//go:build !windows
+
+package logger
+
+import (
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestFlushOnSignal(t *testing.T) {
+	// 先注册业务自己的监听，模拟与业务的信号处理共存，也避免重新发送的信号终止测试进程
+	userCh := make(chan os.Signal, 2)
+	signal.Notify(userCh, syscall.SIGUSR1)
+	defer signal.Stop(userCh)
+
+	fileName := filepath.Join(t.TempDir(), "app.log")
+	l, closeFunc, _, err := NewLogger(nil, &Config{
+		FileName:      fileName,
+		RotateRule:    "no",
+		FlushDuration: 3600 * 1000, // 不依赖定期刷新
+	})
+	if err != nil {
+		t.Fatalf("NewLogger() error = %v", err)
+	}
+
+	closed := make(chan struct{})
+	stop := FlushOnSignal(func() error {
+		defer close(closed)
+		return closeFunc()
+	}, syscall.SIGUSR1)
+	defer stop()
+
+	for i := 0; i < 100; i++ {
+		l.Info("before signal", "i", i)
+	}
+
+	if err := syscall.Kill(os.Getpid(), syscall.SIGUSR1); err != nil {
+		t.Fatalf("send signal: %v", err)
+	}
+	select {
+	case <-closed:
+	case <-time.After(2 * time.Second):
+		t.Fatal("closeFunc should be called on signal")
+	}
+
+	// 业务的监听同样收到信号
+	select {
+	case <-userCh:
+	case <-time.After(2 * time.Second):
+		t.Fatal("user signal handler should receive the signal")
+	}
+
+	content, err := os.ReadFile(fileName)
+	if err != nil {
+		t.Fatalf("read log file: %v", err)
+	}
+	if got := strings.Count(string(content), "msg=before signal"); got != 100 {
+		t.Errorf("persisted %d records, want 100", got)
+	}
+}
+
+func TestFlushOnSignal_Stop(t *testing.T) {
+	called := make(chan struct{}, 1)
+	stop := FlushOnSignal(func() error {
+		called <- struct{}{}
+		return nil
+	}, syscall.SIGUSR2)
+	stop()
+	stop()
+
+	// 取消监听后由业务的监听接收信号，closeFunc 不再被调用
+	userCh := make(chan os.Signal, 1)
+	signal.Notify(userCh, syscall.SIGUSR2)
+	defer signal.Stop(userCh)
+	time.Sleep(10 * time.Millisecond)
+	if err := syscall.Kill(os.Getpid(), syscall.SIGUSR2); err != nil {
+		t.Fatalf("send signal: %v", err)
+	}
+	<-userCh
+	select {
+	case <-called:
+		t.Error("closeFunc should not be called after stop")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
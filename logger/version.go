@@ -0,0 +1,57 @@
+package logger
+
+import (
+	"runtime/debug"
+	"sync/atomic"
+)
+
+const versionKey = "version"
+
+var version atomic.Pointer[string]
+
+// SetVersion 设置服务版本号，开启 Config.AddVersion 后每行日志都会带上 version=
+// 需在 NewLogger 之前调用，未设置时使用构建信息中的版本
+func SetVersion(v string) {
+	version.Store(&v)
+}
+
+// Version 返回当前的服务版本号
+// 未通过 SetVersion 设置时，从 debug.ReadBuildInfo 读取：
+// 优先使用主模块版本，本地构建（(devel)）时使用 VCS 提交号
+func Version() string {
+	if v := version.Load(); v != nil {
+		return *v
+	}
+	return buildInfoVersion()
+}
+
+// buildInfoVersion 从构建信息中读取版本号，无法读取时返回空字符串
+func buildInfoVersion() string {
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+	if bi.Main.Version != "" && bi.Main.Version != "(devel)" {
+		return bi.Main.Version
+	}
+
+	var revision, modified string
+	for _, s := range bi.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			revision = s.Value
+		case "vcs.modified":
+			modified = s.Value
+		}
+	}
+	if revision == "" {
+		return bi.Main.Version
+	}
+	if len(revision) > 12 {
+		revision = revision[:12]
+	}
+	if modified == "true" {
+		revision += "-dirty"
+	}
+	return revision
+}
@@ -0,0 +1,62 @@
+package logger
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+type nopWriteCloser struct {
+	bytes.Buffer
+}
+
+func (w *nopWriteCloser) Close() error { return nil }
+
+func newTestLogger(t *testing.T, conf *Config) (*slog.Logger, *nopWriteCloser) {
+	t.Helper()
+	w := &nopWriteCloser{}
+	conf.FileName = "test.log"
+	conf.writer = w
+	l, closeFn, _, err := NewLogger(nil, conf)
+	if err != nil {
+		t.Fatalf("NewLogger: %v", err)
+	}
+	t.Cleanup(func() { _ = closeFn() })
+	return l, w
+}
+
+func TestSetVersion(t *testing.T) {
+	t.Cleanup(func() { version.Store(nil) })
+	SetVersion("v1.2.3")
+
+	l, w := newTestLogger(t, &Config{Level: slog.LevelInfo, AddVersion: true})
+	l.Info("hello")
+	if !strings.Contains(w.String(), " version=v1.2.3") {
+		t.Errorf("output = %q, want version=v1.2.3", w.String())
+	}
+
+	l, w = newTestLogger(t, &Config{Level: slog.LevelInfo})
+	l.Info("hello")
+	if strings.Contains(w.String(), "version=") {
+		t.Errorf("version should not be logged without AddVersion: %q", w.String())
+	}
+}
+
+func TestVersion_BuildInfoFallback(t *testing.T) {
+	version.Store(nil)
+
+	want := buildInfoVersion()
+	if want == "" {
+		t.Fatal("build info version should be available in test binary")
+	}
+	if got := Version(); got != want {
+		t.Errorf("Version() = %q, want %q", got, want)
+	}
+
+	l, w := newTestLogger(t, &Config{Level: slog.LevelInfo, AddVersion: true})
+	l.Info("hello")
+	if !strings.Contains(w.String(), " version="+want) {
+		t.Errorf("output = %q, want version=%s", w.String(), want)
+	}
+}
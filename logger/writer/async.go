@@ -1,8 +1,11 @@
 package writer
 
 import (
+	"context"
+	"fmt"
 	"io"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -12,69 +15,254 @@ import (
 //	timeout 写超时时间，可以为0，若为0将不超时，阻塞写；若设置为>0的值，当writeTo消费比实际写入多，buf满了将丢弃当前数据
 //	writeTo 实际写入的writer
 func NewAsync(bufSize int, timeout time.Duration, writeTo io.WriteCloser) io.WriteCloser {
+	return NewAsyncWithOption(&AsyncOption{
+		BufSize: bufSize,
+		Timeout: timeout,
+	}, writeTo)
+}
+
+// AsyncOption NewAsyncWithOption 的参数
+type AsyncOption struct {
+	// 异步队列大小
+	BufSize int
+
+	// 写超时时间，同 NewAsync 的 timeout
+	Timeout time.Duration
+
+	// OnError 实际写入 writeTo 出错时的回调，可用于统计或告警
+	// 回调在独立的 goroutine 中异步执行，请勿在回调中向同一个 writer 写日志
+	OnError func(error)
+}
+
+// NewAsyncWithOption 使用 AsyncOption 创建一个异步的writer
+func NewAsyncWithOption(opt *AsyncOption, writeTo io.WriteCloser) io.WriteCloser {
 	w := &asyncWriter{
-		msgs:    make(chan []byte, bufSize),
-		timeout: timeout,
-		raw:     writeTo,
-		done:    make(chan struct{}),
+		msgs:        make(chan asyncMsg, opt.BufSize),
+		timeout:     opt.Timeout,
+		raw:         writeTo,
+		lock:        make(chan struct{}, 1),
+		closing:     make(chan struct{}),
+		sendersDone: make(chan struct{}),
+		done:        make(chan struct{}),
+		abort:       make(chan struct{}),
+		notifier:    newErrorNotifier(opt.OnError),
 	}
 	go w.consumer()
 	return w
 }
 
+// asyncMsg 队列中的一条消息，flushed 不为 nil 时为 Flush 请求，consumer 处理到它时刷新 writeTo 并回复结果
+type asyncMsg struct {
+	p       []byte
+	flushed chan error
+}
+
+// asyncWriter 向队列发送消息时不持有锁，锁只保护 closed 与 senders 的登记，
+// 关闭时阻塞在队列已满上的 Write / Flush 继续等待入队，ctx 结束后才被放弃
 type asyncWriter struct {
-	msgs    chan []byte
+	msgs    chan asyncMsg
 	closed  bool
 	timeout time.Duration
 
-	raw  io.WriteCloser
-	done chan struct{}
-	mu   sync.Mutex
+	raw io.WriteCloser
+
+	lock        chan struct{}  // 容量为 1 的锁，CloseContext 可以在 ctx 结束时放弃等待
+	senders     sync.WaitGroup // 正在向 msgs 发送的 Write / Flush
+	closing     chan struct{}  // CloseContext 超时后关闭，放弃阻塞在发送上的 Write / Flush
+	cutOff      atomic.Int64   // 因 closing 放弃的 Write 条数，计入丢弃条数
+	sendersDone chan struct{}  // 关闭且所有发送结束后关闭，此后 msgs 不会再有新消息
+	done        chan struct{}  // consumer 退出后关闭
+	abort       chan struct{}  // CloseContext 超时后关闭，consumer 不再写入
+
+	notifier *errorNotifier
 }
 
 func (a *asyncWriter) consumer() {
-	for p := range a.msgs {
-		_, _ = a.raw.Write(p)
+	defer close(a.done)
+	for {
+		// 优先检查 abort，超时后写完当前这条即退出
+		select {
+		case <-a.abort:
+			return
+		default:
+		}
+
+		select {
+		case msg := <-a.msgs:
+			a.handle(msg)
+		case <-a.sendersDone:
+			a.drain()
+			return
+		case <-a.abort:
+			return
+		}
 	}
-	a.done <- struct{}{}
 }
 
-func (a *asyncWriter) Write(p []byte) (n int, err error) {
-	a.mu.Lock()
-	defer a.mu.Unlock()
+// drain 关闭后写完队列中剩余的消息，abort 后停止
+func (a *asyncWriter) drain() {
+	for {
+		select {
+		case <-a.abort:
+			return
+		default:
+		}
 
+		select {
+		case msg := <-a.msgs:
+			a.handle(msg)
+		default:
+			return
+		}
+	}
+}
+
+func (a *asyncWriter) handle(msg asyncMsg) {
+	if msg.flushed != nil {
+		msg.flushed <- a.flushRaw()
+		return
+	}
+	if _, err := a.raw.Write(msg.p); err != nil {
+		a.notifier.notify(err)
+	}
+}
+
+// acquire 未关闭时登记一个发送者，已关闭时返回 false
+func (a *asyncWriter) acquire() bool {
+	a.lock <- struct{}{}
+	defer func() { <-a.lock }()
 	if a.closed {
+		return false
+	}
+	a.senders.Add(1)
+	return true
+}
+
+func (a *asyncWriter) Write(p []byte) (n int, err error) {
+	if !a.acquire() {
 		return 0, io.ErrClosedPipe
 	}
+	defer a.senders.Done()
 
 	// 复制数据避免 buffer 被复用导致的数据竞争
 	buf := make([]byte, len(p))
 	copy(buf, p)
 
-	if a.timeout == 0 {
-		a.msgs <- buf
-		return len(p), nil
+	var timeout <-chan time.Time
+	if a.timeout > 0 {
+		timer := time.NewTimer(a.timeout)
+		defer timer.Stop()
+		timeout = timer.C
 	}
 	select {
-	case a.msgs <- buf:
+	case a.msgs <- asyncMsg{p: buf}:
 		return len(p), nil
-	case <-time.After(a.timeout):
+	case <-a.closing:
+		a.cutOff.Add(1)
+		return 0, io.ErrClosedPipe
+	case <-timeout:
 		return 0, ErrWriteTimeout
 	}
 }
 
+// Flush 等待调用前写入的日志全部交给 writeTo，writeTo 实现了 Flusher 时再将其刷新落盘
+// 队列已满时会等待队列有空位，不受写超时限制；可并发调用，关闭后调用直接返回 nil
+func (a *asyncWriter) Flush() error {
+	if !a.acquire() {
+		return nil
+	}
+	flushed := make(chan error, 1)
+	select {
+	case a.msgs <- asyncMsg{flushed: flushed}:
+		a.senders.Done()
+	case <-a.closing:
+		// 关闭超时，队列中的日志已被丢弃
+		a.senders.Done()
+		return ErrCloseTimeout
+	}
+
+	select {
+	case err := <-flushed:
+		return err
+	case <-a.done:
+		// 等待期间被关闭：正常关闭时 consumer 退出前已处理 Flush 请求，超时关闭时请求被丢弃
+		select {
+		case err := <-flushed:
+			return err
+		default:
+			return ErrCloseTimeout
+		}
+	}
+}
+
+// flushRaw 刷新 writeTo，未实现 Flusher 时为空操作
+func (a *asyncWriter) flushRaw() error {
+	if f, ok := a.raw.(Flusher); ok {
+		return f.Flush()
+	}
+	return nil
+}
+
 func (a *asyncWriter) Close() error {
-	a.mu.Lock()
-	defer a.mu.Unlock()
+	return a.CloseContext(context.Background())
+}
+
+// CloseContext 关闭 writer，等待队列中的日志以及阻塞在队列已满上的 Write 写完后关闭 writeTo
+// ctx 先结束时不再等待，丢弃队列中剩余的日志，阻塞的 Write 返回 io.ErrClosedPipe，
+// 并返回 ErrCloseTimeout（包含这两部分的丢弃条数）；
+// consumer 可能在超时后写完正在写的一条并再取出写入一条，这些不计入丢弃条数，丢弃条数即未写入的条数；
+// writeTo 在 consumer 退出后于后台关闭
+func (a *asyncWriter) CloseContext(ctx context.Context) error {
+	select {
+	case a.lock <- struct{}{}:
+	case <-ctx.Done():
+		return fmt.Errorf("%w: %w", ErrCloseTimeout, ctx.Err())
+	}
 	if a.closed {
+		<-a.lock
 		return nil
 	}
+	a.closed = true
+	<-a.lock
 
-	close(a.msgs)
-	<-a.done
+	// consumer 继续消费，阻塞的发送者得以入队，全部结束后 consumer 写完队列并退出
+	go func() {
+		a.senders.Wait()
+		close(a.sendersDone)
+	}()
 
-	a.closed = true
-	return a.raw.Close()
+	select {
+	case <-a.done:
+		a.notifier.close()
+		return a.raw.Close()
+	case <-ctx.Done():
+	}
+
+	close(a.abort)
+	// 发送者看到 closing 后立即返回，之后 msgs 不会再有新消息
+	close(a.closing)
+	<-a.sendersDone
+	dropped := int(a.cutOff.Load())
+	for drained := false; !drained; {
+		select {
+		case msg := <-a.msgs:
+			if msg.flushed == nil {
+				dropped++
+			}
+		default:
+			drained = true
+		}
+	}
+	go func() {
+		<-a.done
+		a.notifier.close()
+		_ = a.raw.Close()
+	}()
+	return fmt.Errorf("%w: %d records dropped: %w", ErrCloseTimeout, dropped, ctx.Err())
 }
 
-var _ io.WriteCloser = (*asyncWriter)(nil)
+var (
+	_ io.WriteCloser = (*asyncWriter)(nil)
+	_ ContextCloser  = (*asyncWriter)(nil)
+	_ Flusher        = (*asyncWriter)(nil)
+)
@@ -0,0 +1,257 @@
+package writer
+
+import (
+	"context"
+	"errors"
+	"io"
+	"regexp"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var errDiskFull = errors.New("disk full")
+
+// errWriteCloser 总是写入失败的 writer
+type errWriteCloser struct{}
+
+func (errWriteCloser) Write([]byte) (int, error) { return 0, errDiskFull }
+
+func (errWriteCloser) Close() error { return nil }
+
+func TestAsyncWriter_OnError(t *testing.T) {
+	errs := make(chan error, 10)
+	w := NewAsyncWithOption(&AsyncOption{
+		BufSize: 10,
+		OnError: func(err error) {
+			errs <- err
+		},
+	}, errWriteCloser{})
+
+	if _, err := w.Write([]byte("hello\n")); err != nil {
+		t.Fatalf("async write should not fail: %v", err)
+	}
+
+	select {
+	case err := <-errs:
+		if !errors.Is(err, errDiskFull) {
+			t.Fatalf("OnError got %v, want %v", err, errDiskFull)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("OnError was not called")
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatalf("close failed: %v", err)
+	}
+}
+
+// slowWriteCloser 每次写入耗时 delay 的 writer
+type slowWriteCloser struct {
+	delay  time.Duration
+	writes atomic.Int64
+	closed chan struct{}
+}
+
+func newSlowWriteCloser(delay time.Duration) *slowWriteCloser {
+	return &slowWriteCloser{delay: delay, closed: make(chan struct{})}
+}
+
+func (w *slowWriteCloser) Write(p []byte) (int, error) {
+	time.Sleep(w.delay)
+	w.writes.Add(1)
+	return len(p), nil
+}
+
+func (w *slowWriteCloser) Close() error {
+	close(w.closed)
+	return nil
+}
+
+func TestAsyncWriter_CloseContext(t *testing.T) {
+	t.Run("timeout drops buffered records", func(t *testing.T) {
+		raw := newSlowWriteCloser(10 * time.Millisecond)
+		w := NewAsync(100, 0, raw)
+		for i := 0; i < 100; i++ {
+			if _, err := w.Write([]byte("hello\n")); err != nil {
+				t.Fatalf("write failed: %v", err)
+			}
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		start := time.Now()
+		err := w.(ContextCloser).CloseContext(ctx)
+		if elapsed := time.Since(start); elapsed > 200*time.Millisecond {
+			t.Errorf("CloseContext took %v, want about 50ms", elapsed)
+		}
+		if !errors.Is(err, ErrCloseTimeout) || !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("CloseContext() error = %v, want ErrCloseTimeout", err)
+		}
+
+		m := regexp.MustCompile(`(\d+) records dropped`).FindStringSubmatch(err.Error())
+		if m == nil {
+			t.Fatalf("error %q should report dropped records", err)
+		}
+		dropped, _ := strconv.Atoi(m[1])
+
+		// 正在写入的一条写完后在后台关闭 writeTo
+		select {
+		case <-raw.closed:
+		case <-time.After(time.Second):
+			t.Fatal("writeTo was not closed")
+		}
+		if written := int(raw.writes.Load()); written+dropped != 100 || dropped == 0 {
+			t.Errorf("written = %d, dropped = %d, want a total of 100", written, dropped)
+		}
+
+		if _, err := w.Write([]byte("hello\n")); err == nil {
+			t.Error("write after close should fail")
+		}
+	})
+
+	t.Run("drains before deadline", func(t *testing.T) {
+		raw := newSlowWriteCloser(0)
+		w := NewAsync(100, 0, raw)
+		for i := 0; i < 10; i++ {
+			_, _ = w.Write([]byte("hello\n"))
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		if err := w.(ContextCloser).CloseContext(ctx); err != nil {
+			t.Fatalf("CloseContext() error = %v", err)
+		}
+		if n := raw.writes.Load(); n != 10 {
+			t.Errorf("writes = %d, want 10", n)
+		}
+		// 重复关闭为空操作
+		if err := w.Close(); err != nil {
+			t.Errorf("second Close() error = %v", err)
+		}
+	})
+}
+
+// flushRecorder 记录写入内容与 Flush 时已写入条数的 writer
+type flushRecorder struct {
+	mu      sync.Mutex
+	writes  int
+	flushed []int
+}
+
+func (w *flushRecorder) Write(p []byte) (int, error) {
+	time.Sleep(time.Millisecond)
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.writes++
+	return len(p), nil
+}
+
+func (w *flushRecorder) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.flushed = append(w.flushed, w.writes)
+	return nil
+}
+
+func (w *flushRecorder) Close() error { return nil }
+
+func TestAsyncWriter_Flush(t *testing.T) {
+	raw := &flushRecorder{}
+	w := NewAsync(100, 0, raw)
+	for i := 0; i < 20; i++ {
+		_, _ = w.Write([]byte("hello\n"))
+	}
+
+	// Flush 返回时调用前写入的日志都已交给 writeTo 并刷新
+	if err := w.(Flusher).Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+	raw.mu.Lock()
+	flushed := append([]int(nil), raw.flushed...)
+	raw.mu.Unlock()
+	if len(flushed) != 1 || flushed[0] != 20 {
+		t.Errorf("flushed = %v, want [20]", flushed)
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if err := w.(Flusher).Flush(); err != nil {
+		t.Errorf("Flush() after close error = %v", err)
+	}
+}
+
+func TestAsyncWriter_CloseContextWithBlockedWriter(t *testing.T) {
+	raw := newSlowWriteCloser(50 * time.Millisecond)
+	w := NewAsync(2, 0, raw)
+
+	// 队列已满后，生产者阻塞在 Write 上
+	written := make(chan error, 1)
+	go func() {
+		for {
+			if _, err := w.Write([]byte("hello\n")); err != nil {
+				written <- err
+				return
+			}
+		}
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err := w.(ContextCloser).CloseContext(ctx)
+	if elapsed := time.Since(start); elapsed > 150*time.Millisecond {
+		t.Errorf("CloseContext took %v, should not wait for the blocked writer", elapsed)
+	}
+	if !errors.Is(err, ErrCloseTimeout) {
+		t.Errorf("CloseContext() error = %v, want ErrCloseTimeout", err)
+	}
+	// 被放弃的 Write 计入丢弃条数
+	if m := regexp.MustCompile(`(\d+) records dropped`).FindStringSubmatch(err.Error()); m == nil || m[1] == "0" {
+		t.Errorf("error %q should count the blocked writer as dropped", err)
+	}
+
+	select {
+	case err := <-written:
+		if !errors.Is(err, io.ErrClosedPipe) {
+			t.Errorf("blocked Write() error = %v, want io.ErrClosedPipe", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("blocked Write was not released by close")
+	}
+
+	// 关闭后 Flush 直接返回
+	if err := w.(Flusher).Flush(); err != nil {
+		t.Errorf("Flush() after close error = %v", err)
+	}
+}
+
+func TestAsyncWriter_CloseWaitsForBlockedWriter(t *testing.T) {
+	raw := newSlowWriteCloser(20 * time.Millisecond)
+	w := NewAsync(2, 0, raw)
+	for i := 0; i < 3; i++ {
+		_, _ = w.Write([]byte("hello\n"))
+	}
+
+	// 队列已满，这条 Write 阻塞等待入队
+	written := make(chan error, 1)
+	go func() {
+		_, err := w.Write([]byte("blocked\n"))
+		written <- err
+	}()
+	time.Sleep(5 * time.Millisecond)
+
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if err := <-written; err != nil {
+		t.Errorf("blocked Write() error = %v, want nil", err)
+	}
+	if n := raw.writes.Load(); n != 4 {
+		t.Errorf("writes = %d, want 4 including the blocked record", n)
+	}
+}
@@ -1,6 +1,7 @@
 package writer
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
@@ -8,12 +9,26 @@ import (
 	"runtime"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
 // ErrWriteTimeout 写超时错误
 var ErrWriteTimeout = errors.New("write timeout")
 
+// ErrCloseTimeout 关闭时未能在 ctx 结束前写完缓冲中的日志
+var ErrCloseTimeout = errors.New("close timeout")
+
+// ContextCloser 支持在 ctx 结束时放弃等待的关闭，NewAsync 创建的 writer 实现了该接口，NewEncoding 转发给 inner
+type ContextCloser interface {
+	CloseContext(ctx context.Context) error
+}
+
+// Flusher 支持将缓冲中的内容刷新落盘，NewAsync 与 NewRotate 创建的 writer 实现了该接口，NewEncoding 转发给 inner
+type Flusher interface {
+	Flush() error
+}
+
 func log2Stderr(format string, vs ...interface{}) {
 	_, file, line, _ := runtime.Caller(1)
 	prefix := strings.Join([]string{
@@ -80,3 +95,75 @@ func keepDirExists(dir string) error {
 }
 
 var nowFunc = time.Now
+
+// errorNotifier 将写入错误异步地通知给回调函数
+// 回调在独立的 goroutine 中执行，不会阻塞写入，也不会在持有 writer 锁时被调用，
+// 避免回调中再次写日志造成死锁；通知队列满时丢弃错误
+type errorNotifier struct {
+	fn     func(error)
+	errs   chan error
+	mu     sync.Mutex
+	closed bool
+	done   chan struct{}
+}
+
+// newErrorNotifier 创建错误通知器，fn 为 nil 时返回 nil，nil 通知器的方法均为空操作
+func newErrorNotifier(fn func(error)) *errorNotifier {
+	if fn == nil {
+		return nil
+	}
+	n := &errorNotifier{
+		fn:   fn,
+		errs: make(chan error, 64),
+		done: make(chan struct{}),
+	}
+	go n.run()
+	return n
+}
+
+func (n *errorNotifier) run() {
+	defer close(n.done)
+	for err := range n.errs {
+		n.call(err)
+	}
+}
+
+func (n *errorNotifier) call(err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log2Stderr("onError callback panic: %v\n", r)
+		}
+	}()
+	n.fn(err)
+}
+
+func (n *errorNotifier) notify(err error) {
+	if n == nil || err == nil {
+		return
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.closed {
+		return
+	}
+	select {
+	case n.errs <- err:
+	default:
+	}
+}
+
+// close 停止通知，并等待已入队的错误回调完成
+func (n *errorNotifier) close() {
+	if n == nil {
+		return
+	}
+	n.mu.Lock()
+	if n.closed {
+		n.mu.Unlock()
+		return
+	}
+	n.closed = true
+	close(n.errs)
+	n.mu.Unlock()
+	<-n.done
+}
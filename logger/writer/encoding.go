@@ -0,0 +1,67 @@
+package writer
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+
+	"golang.org/x/text/encoding"
+)
+
+// NewEncoding 创建一个将 UTF-8 日志转码为 enc 后写入 inner 的 writer，用于要求 GBK、Shift-JIS 等编码的旧系统
+// 每次 Write 需为完整的 UTF-8 内容（Handler 每次写入一整行日志）；目标编码无法表示的字符替换为 \x1a
+// inner 实现了 Flusher、ContextCloser 时，返回的 writer 的 Flush、CloseContext 转发给 inner
+func NewEncoding(inner io.WriteCloser, enc encoding.Encoding) io.WriteCloser {
+	return &encodingWriter{
+		raw:     inner,
+		encoder: encoding.ReplaceUnsupported(enc.NewEncoder()),
+	}
+}
+
+type encodingWriter struct {
+	raw     io.WriteCloser
+	encoder *encoding.Encoder // 有内部状态，需加锁使用
+	mu      sync.Mutex
+}
+
+// Write 转码后写入，成功时返回 len(p)，即转码前的字节数
+func (e *encodingWriter) Write(p []byte) (int, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	data, err := e.encoder.Bytes(p)
+	if err != nil {
+		return 0, fmt.Errorf("encode log: %w", err)
+	}
+	if _, err = e.raw.Write(data); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (e *encodingWriter) Close() error {
+	return e.raw.Close()
+}
+
+// Flush inner 实现了 Flusher 时转发给 inner，否则为空操作
+func (e *encodingWriter) Flush() error {
+	if f, ok := e.raw.(Flusher); ok {
+		return f.Flush()
+	}
+	return nil
+}
+
+// CloseContext inner 实现了 ContextCloser 时转发给 inner，否则同 Close
+func (e *encodingWriter) CloseContext(ctx context.Context) error {
+	if c, ok := e.raw.(ContextCloser); ok {
+		return c.CloseContext(ctx)
+	}
+	return e.raw.Close()
+}
+
+var (
+	_ io.WriteCloser = (*encodingWriter)(nil)
+	_ ContextCloser  = (*encodingWriter)(nil)
+	_ Flusher        = (*encodingWriter)(nil)
+)
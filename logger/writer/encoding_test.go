@@ -0,0 +1,97 @@
+package writer
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/encoding/simplifiedchinese"
+)
+
+type bufferCloser struct {
+	bytes.Buffer
+	closed bool
+}
+
+func (b *bufferCloser) Close() error {
+	b.closed = true
+	return nil
+}
+
+func TestEncodingWriter(t *testing.T) {
+	inner := &bufferCloser{}
+	w := NewEncoding(inner, simplifiedchinese.GBK)
+
+	line := "INFO: msg=你好 user=tom\n"
+	n, err := io.WriteString(w, line)
+	if err != nil || n != len(line) {
+		t.Fatalf("Write() = (%d, %v), want (%d, nil)", n, err, len(line))
+	}
+	want := append([]byte("INFO: msg="), 0xC4, 0xE3, 0xBA, 0xC3)
+	want = append(want, " user=tom\n"...)
+	if !bytes.Equal(inner.Bytes(), want) {
+		t.Errorf("GBK output = % x, want % x", inner.Bytes(), want)
+	}
+
+	// 目标编码无法表示的字符被替换，不会导致写入失败
+	inner.Reset()
+	if _, err = io.WriteString(w, "a😀b"); err != nil {
+		t.Fatalf("Write() unsupported rune error = %v", err)
+	}
+	if got := inner.String(); got != "a\x1ab" {
+		t.Errorf("output = %q, want unsupported rune replaced", got)
+	}
+
+	if err = w.Close(); err != nil || !inner.closed {
+		t.Errorf("Close() = %v, inner closed = %v", err, inner.closed)
+	}
+}
+
+func TestEncodingWriter_ShiftJIS(t *testing.T) {
+	inner := &bufferCloser{}
+	w := NewEncoding(inner, japanese.ShiftJIS)
+	if _, err := io.WriteString(w, "ログ"); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if want := []byte{0x83, 0x8D, 0x83, 0x4F}; !bytes.Equal(inner.Bytes(), want) {
+		t.Errorf("Shift-JIS output = % x, want % x", inner.Bytes(), want)
+	}
+}
+
+func TestEncodingWriter_ForwardsFlushAndCloseContext(t *testing.T) {
+	raw := &flushRecorder{}
+	w := NewEncoding(NewAsync(10, 0, raw), simplifiedchinese.GBK)
+
+	if _, err := io.WriteString(w, "你好\n"); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	// Flush 转发给异步 writer，返回时内容已写入 raw 并刷新
+	if err := w.(Flusher).Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+	raw.mu.Lock()
+	flushed := raw.flushed
+	raw.mu.Unlock()
+	if len(flushed) != 1 || flushed[0] != 1 {
+		t.Errorf("raw flushed = %v, want [1]", flushed)
+	}
+
+	if err := w.(ContextCloser).CloseContext(context.Background()); err != nil {
+		t.Errorf("CloseContext() error = %v", err)
+	}
+	if _, err := io.WriteString(w, "a"); err == nil {
+		t.Error("write after CloseContext should fail")
+	}
+
+	// inner 未实现时 Flush 为空操作，CloseContext 同 Close
+	inner := &bufferCloser{}
+	w = NewEncoding(inner, simplifiedchinese.GBK)
+	if err := w.(Flusher).Flush(); err != nil {
+		t.Errorf("Flush() error = %v", err)
+	}
+	if err := w.(ContextCloser).CloseContext(context.Background()); err != nil || !inner.closed {
+		t.Errorf("CloseContext() = %v, inner closed = %v", err, inner.closed)
+	}
+}
@@ -33,6 +33,15 @@ type RotateOption struct {
 
 	// 保留最多日志文件数，默认为0,不清理
 	MaxFileNum int
+
+	// OnError 写入或刷新文件出错时的回调（如磁盘已满），可用于统计或告警
+	// 回调在独立的 goroutine 中异步执行，请勿在回调中向同一个 writer 写日志
+	OnError func(error)
+
+	// SyncOnFlush 每次 Flush（包括定期刷新和 Close）后调用 fsync，保证内容落到磁盘而不只是操作系统缓存
+	// 可避免机器掉电、内核崩溃时丢失最后的日志，但 fsync 是同步的磁盘 IO，耗时通常在毫秒级，
+	// 刷新频繁或磁盘繁忙时会明显降低写入吞吐，并阻塞持有锁的写入方，默认关闭
+	SyncOnFlush bool
 }
 
 // Check 检查参数是否正确
@@ -57,7 +66,8 @@ func NewRotate(opt *RotateOption) (io.WriteCloser, error) {
 	}
 
 	w := &rotateWriter{
-		opt: opt,
+		opt:      opt,
+		notifier: newErrorNotifier(opt.OnError),
 	}
 	if err := w.init(); err != nil {
 		_ = w.Close()
@@ -82,6 +92,8 @@ type rotateWriter struct {
 
 	// 清理文件时的延迟时间，避免集中清理
 	cleanDelay func() time.Duration
+
+	notifier *errorNotifier
 }
 
 func (f *rotateWriter) init() error {
@@ -203,7 +215,7 @@ func (f *rotateWriter) checkOpened(info RotateInfo) (errResult error) {
 
 	if needNew {
 		if f.outFile != nil {
-			errFlush := f.bufFile.Flush()
+			errFlush := f.flushLocked()
 			errClose := f.outFile.Close()
 
 			if errFlush != nil || errClose != nil {
@@ -259,6 +271,9 @@ func (f *rotateWriter) Write(p []byte) (n int, err error) {
 	}
 
 	n, err = f.bufFile.Write(p)
+	if err != nil {
+		f.notifier.notify(err)
+	}
 
 	if f.bufFile.Buffered() == 0 {
 		f.lastFlush = time.Now()
@@ -274,7 +289,22 @@ func (f *rotateWriter) Flush() error {
 	if f.bufFile == nil {
 		return nil
 	}
-	return f.bufFile.Flush()
+	err := f.flushLocked()
+	if err != nil {
+		f.notifier.notify(err)
+	}
+	return err
+}
+
+// flushLocked 将缓冲区写入文件，开启 SyncOnFlush 时再调用 fsync，调用方需持有 f.mu
+func (f *rotateWriter) flushLocked() error {
+	if err := f.bufFile.Flush(); err != nil {
+		return err
+	}
+	if f.opt.SyncOnFlush && f.outFile != nil {
+		return f.outFile.Sync()
+	}
+	return nil
 }
 
 func (f *rotateWriter) checkFlush(dur time.Duration) {
@@ -295,7 +325,7 @@ func (f *rotateWriter) Close() error {
 	var err1, err2 error
 	f.mu.Lock()
 	if f.bufFile != nil {
-		err1 = f.bufFile.Flush()
+		err1 = f.flushLocked()
 	}
 	if f.outFile != nil {
 		err2 = f.outFile.Close()
@@ -304,13 +334,18 @@ func (f *rotateWriter) Close() error {
 	f.bufFile = nil
 	f.mu.Unlock()
 
+	f.notifier.close()
+
 	if err1 == nil && err2 == nil {
 		return nil
 	}
 	return fmt.Errorf("flush:%w, close:%v", err1, err2)
 }
 
-var _ io.WriteCloser = (*rotateWriter)(nil)
+var (
+	_ io.WriteCloser = (*rotateWriter)(nil)
+	_ Flusher        = (*rotateWriter)(nil)
+)
 
 // checkSymlink 检查并保持软连正确
 func checkSymlink(info RotateInfo) error {
@@ -57,8 +57,24 @@ func NewRotateProducer(duration time.Duration, producer func() RotateInfo) Rotat
 	}
 }
 
+// NewRotateProducerInLocation 创建一个按指定时区计算切割边界的日志切割分发器
+func NewRotateProducerInLocation(duration time.Duration, loc *time.Location, producer func() RotateInfo) RotateProducer {
+	return &rotateProducer{
+		p: timer.NewProducerInLocation(duration, loc, func() interface{} {
+			return producer()
+		}),
+	}
+}
+
 // NewSimpleRotateProducer 使用已有规则生成具有自动定时变化文件名的分发器
 func NewSimpleRotateProducer(rule string, fileNamePrefix string) (RotateProducer, error) {
+	return NewSimpleRotateProducerInLocation(rule, fileNamePrefix, time.Local)
+}
+
+// NewSimpleRotateProducerInLocation 使用已有规则生成分发器，切割边界和文件名后缀均按 loc 时区计算
+// 如 rule=1day, loc=Asia/Shanghai，即使服务器使用 UTC，也会在上海时间0点切割
+// loc 为 nil 时使用 time.Local
+func NewSimpleRotateProducerInLocation(rule string, fileNamePrefix string, loc *time.Location) (RotateProducer, error) {
 	if fileNamePrefix == "" {
 		return nil, fmt.Errorf("fileNamePrefix is empty")
 	}
@@ -67,13 +83,17 @@ func NewSimpleRotateProducer(rule string, fileNamePrefix string) (RotateProducer
 		return nil, fmt.Errorf("rule=%q not supported yet", rule)
 	}
 
-	return NewRotateProducer(rt.Duration, func() RotateInfo {
+	if loc == nil {
+		loc = time.Local
+	}
+
+	return NewRotateProducerInLocation(rt.Duration, loc, func() RotateInfo {
 		return RotateInfo{
 			RawName: fileNamePrefix,
 			Symlink: fileNamePrefix,
 			FilePath: strings.Join([]string{
 				fileNamePrefix,
-				rt.SuffixProducer(),
+				rt.SuffixProducer(loc),
 			}, ""),
 		}
 	}), nil
@@ -101,77 +121,78 @@ func (r *rotateProducer) Stop() error {
 var _ RotateProducer = (*rotateProducer)(nil)
 
 type rotateRule struct {
-	Duration       time.Duration
-	SuffixProducer func() string
+	Duration time.Duration
+	// SuffixProducer 按 loc 时区的当前时间生成文件名后缀
+	SuffixProducer func(loc *time.Location) string
 }
 
 var defaultRotateRules = map[string]*rotateRule{
 	"1hour": {
 		Duration: 1 * time.Hour,
 		// 小时  后缀如  .2020072217
-		SuffixProducer: func() string {
-			return "." + nowFunc().Format("2006010215")
+		SuffixProducer: func(loc *time.Location) string {
+			return "." + nowFunc().In(loc).Format("2006010215")
 		},
 	},
 	"1day": {
 		Duration: 24 * time.Hour,
 		// 天  后缀如  .20200722
-		SuffixProducer: func() string {
-			return "." + nowFunc().Format("20060102")
+		SuffixProducer: func(loc *time.Location) string {
+			return "." + nowFunc().In(loc).Format("20060102")
 		},
 	},
 	"no": {
 		Duration: 0,
 		// 无后缀
-		SuffixProducer: func() string {
+		SuffixProducer: func(*time.Location) string {
 			return ""
 		},
 	},
 	"1min": {
 		Duration: 1 * time.Minute,
 		// 1分钟 后缀如  .202007221700  .202007221701  .202007221702  .202007221759
-		SuffixProducer: func() string {
-			now := nowFunc()
+		SuffixProducer: func(loc *time.Location) string {
+			now := nowFunc().In(loc)
 			return "." + now.Format("2006010215") + fmt.Sprintf("%02d", now.Minute())
 		},
 	},
 	"5min": {
 		Duration: 5 * time.Minute,
 		// 5分钟 后缀如  .202007221700  .202007221705  .202007221710  .202007221715
-		SuffixProducer: func() string {
-			now := nowFunc()
+		SuffixProducer: func(loc *time.Location) string {
+			now := nowFunc().In(loc)
 			return "." + now.Format("2006010215") + fmt.Sprintf("%02d", now.Minute()/5*5)
 		},
 	},
 	"10min": {
 		Duration: 10 * time.Minute,
 		// 10分钟 后缀如  .202007221700  .202007221710  .202007221720  .202007221750
-		SuffixProducer: func() string {
-			now := nowFunc()
+		SuffixProducer: func(loc *time.Location) string {
+			now := nowFunc().In(loc)
 			return "." + now.Format("2006010215") + fmt.Sprintf("%02d", now.Minute()/10*10)
 		},
 	},
 	"15min": {
 		Duration: 15 * time.Minute,
 		// 15分钟 后缀如  .202007221700  .202007221715  .202007221730  .202007221745
-		SuffixProducer: func() string {
-			now := nowFunc()
+		SuffixProducer: func(loc *time.Location) string {
+			now := nowFunc().In(loc)
 			return "." + now.Format("2006010215") + fmt.Sprintf("%02d", now.Minute()/15*15)
 		},
 	},
 	"30min": {
 		Duration: 30 * time.Minute,
 		// 30分钟 后缀如  .202007221700  .202007221730
-		SuffixProducer: func() string {
-			now := nowFunc()
+		SuffixProducer: func(loc *time.Location) string {
+			now := nowFunc().In(loc)
 			return "." + now.Format("2006010215") + fmt.Sprintf("%02d", now.Minute()/30*30)
 		},
 	},
 	"": {
 		Duration: 1 * time.Hour,
 		// 小时  后缀如  .2020072217
-		SuffixProducer: func() string {
-			return "." + nowFunc().Format("2006010215")
+		SuffixProducer: func(loc *time.Location) string {
+			return "." + nowFunc().In(loc).Format("2006010215")
 		},
 	},
 }
@@ -201,8 +222,10 @@ func RegisterRotateRule(rule string, duration time.Duration, suffix func() strin
 		return errors.New("rule already exists")
 	}
 	defaultRotateRules[rule] = &rotateRule{
-		Duration:       duration,
-		SuffixProducer: suffix,
+		Duration: duration,
+		SuffixProducer: func(*time.Location) string {
+			return suffix()
+		},
 	}
 	return nil
 }
@@ -0,0 +1,36 @@
+package writer
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSimpleRotateProducerInLocation_CrossLocalMidnight(t *testing.T) {
+	oldNow := nowFunc
+	defer func() { nowFunc = oldNow }()
+
+	cst := time.FixedZone("CST", 8*3600)
+	// UTC 15:59:59 为 UTC+8 的 2024-01-01 23:59:59
+	now := time.Date(2024, 1, 1, 15, 59, 59, 0, time.UTC)
+	nowFunc = func() time.Time { return now }
+
+	rp, err := NewSimpleRotateProducerInLocation("1day", "/tmp/app.log", cst)
+	if err != nil {
+		t.Fatalf("NewSimpleRotateProducerInLocation failed: %v", err)
+	}
+	defer func() { _ = rp.Stop() }()
+
+	if got := rp.Get().FilePath; got != "/tmp/app.log.20240101" {
+		t.Fatalf("FilePath before local midnight = %q", got)
+	}
+
+	rule := defaultRotateRules["1day"]
+	now = now.Add(time.Second)
+	if got := rule.SuffixProducer(cst); got != ".20240102" {
+		t.Fatalf("suffix after local midnight = %q, want %q", got, ".20240102")
+	}
+	// 同一时刻 UTC 仍然是 1月1日
+	if got := rule.SuffixProducer(time.UTC); got != ".20240101" {
+		t.Fatalf("suffix in UTC = %q, want %q", got, ".20240101")
+	}
+}
@@ -1,9 +1,12 @@
 package writer
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 )
 
 type staticRotateProducer struct {
@@ -60,3 +63,89 @@ func TestRotateWriter_PreExistingFileOnStartup(t *testing.T) {
 		t.Fatalf("unexpected log content: %q", string(content))
 	}
 }
+
+func TestRotateWriter_OnError(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "app.log")
+	producer := &staticRotateProducer{
+		info: RotateInfo{
+			RawName:  logPath,
+			FilePath: logPath,
+		},
+	}
+
+	errs := make(chan error, 10)
+	w, err := NewRotate(&RotateOption{
+		FileProducer: producer,
+		OnError: func(err error) {
+			errs <- err
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewRotate failed: %v", err)
+	}
+
+	rw := w.(*rotateWriter)
+	if _, err = w.Write([]byte("hello\n")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	// 关闭底层文件，模拟落盘失败
+	_ = rw.outFile.Close()
+	if err = rw.Flush(); err == nil {
+		t.Fatal("flush should fail after file closed")
+	}
+
+	select {
+	case got := <-errs:
+		if got == nil {
+			t.Fatal("OnError got nil error")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("OnError was not called")
+	}
+	_ = w.Close()
+}
+
+func TestRotateWriter_SyncOnFlushClose(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "app.log")
+	producer := &staticRotateProducer{
+		info: RotateInfo{
+			RawName:  logPath,
+			FilePath: logPath,
+		},
+	}
+
+	rw, err := NewRotate(&RotateOption{FileProducer: producer, SyncOnFlush: true})
+	if err != nil {
+		t.Fatalf("NewRotate failed: %v", err)
+	}
+	// 与 logger 相同，rotate writer 外包一层 async writer
+	w := NewAsync(16, 0, rw)
+
+	const lines = 1000
+	for i := 0; i < lines; i++ {
+		if _, err = fmt.Fprintf(w, "line %d\n", i); err != nil {
+			t.Fatalf("write failed: %v", err)
+		}
+	}
+	if err = rw.(*rotateWriter).Flush(); err != nil {
+		t.Fatalf("flush with sync failed: %v", err)
+	}
+	// Close 需要先排空异步队列并落盘后才返回
+	if err = w.Close(); err != nil {
+		t.Fatalf("close failed: %v", err)
+	}
+
+	content, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("read log file failed: %v", err)
+	}
+	got := strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+	if len(got) != lines {
+		t.Fatalf("got %d lines after close, want %d", len(got), lines)
+	}
+	for i, line := range got {
+		if want := fmt.Sprintf("line %d", i); line != want {
+			t.Fatalf("line %d = %q, want %q", i, line, want)
+		}
+	}
+}
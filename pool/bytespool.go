@@ -12,7 +12,7 @@ var (
 
 // BytesPool 复用 bytes.Buffer 的对象池
 type BytesPool interface {
-	// Get 一个bytes.Buffer。
+	// Get 一个bytes.Buffer，返回的 Buffer 保证是 Reset 过的。
 	Get() *bytes.Buffer
 
 	// Put 一个bytes.Buffer，Put 内部需要对 Buffer 统一做 Reset。
@@ -40,7 +40,13 @@ type bytesPool struct {
 }
 
 func (p *bytesPool) Get() *bytes.Buffer {
-	return p.pool.Get().(*bytes.Buffer)
+	b, ok := p.pool.Get().(*bytes.Buffer)
+	if !ok || b == nil {
+		return bytes.NewBuffer(nil)
+	}
+	// 防御性 Reset：使用方若在 Put 之后继续写入（如重复 Put），可能残留脏数据
+	b.Reset()
+	return b
 }
 
 func (p *bytesPool) Put(b *bytes.Buffer) {
@@ -0,0 +1,23 @@
+package pool
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+)
+
+func TestBytesPool_GetReturnsResetBuffer(t *testing.T) {
+	// 由 New 返回脏 Buffer，复用是确定的，不依赖 sync.Pool 是否保留 Put 进去的对象
+	p := &bytesPool{
+		pool: &sync.Pool{
+			New: func() interface{} {
+				return bytes.NewBufferString("dirty")
+			},
+		},
+	}
+
+	got := p.Get()
+	if got.Len() != 0 {
+		t.Fatalf("Get() returned dirty buffer: %q", got.String())
+	}
+}
@@ -0,0 +1,74 @@
+package pool
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/Twelveeee/golib/constant"
+)
+
+// RequestPool 请求级的 bytes.Buffer 对象池，在单个请求的生命周期内复用 Buffer，不经过 sync.Pool
+// 本地没有空闲 Buffer 时从 parent 获取，请求结束时调用 Release 将 Buffer 归还给 parent
+// 只能在同一个 goroutine 中使用，不是并发安全的；跨 goroutine 使用时请直接使用 GlobalBytesPool
+type RequestPool struct {
+	parent BytesPool
+	free   []*bytes.Buffer
+}
+
+var _ BytesPool = (*RequestPool)(nil)
+
+// NewRequestPool 创建请求级对象池，parent 为 nil 时使用 GlobalBytesPool
+func NewRequestPool(parent BytesPool) *RequestPool {
+	if parent == nil {
+		parent = GlobalBytesPool
+	}
+	return &RequestPool{parent: parent}
+}
+
+// Get 获取一个 Reset 过的 Buffer，优先复用本请求内 Put 回来的 Buffer
+func (p *RequestPool) Get() *bytes.Buffer {
+	if n := len(p.free); n > 0 {
+		b := p.free[n-1]
+		p.free[n-1] = nil
+		p.free = p.free[:n-1]
+		return b
+	}
+	return p.parent.Get()
+}
+
+// Put 归还 Buffer 供本请求内后续的 Get 复用
+func (p *RequestPool) Put(b *bytes.Buffer) {
+	b.Reset()
+	p.free = append(p.free, b)
+}
+
+// Release 将本请求内空闲的 Buffer 全部归还给 parent，请求结束时调用，之后 RequestPool 仍可继续使用
+func (p *RequestPool) Release() {
+	for i, b := range p.free {
+		p.parent.Put(b)
+		p.free[i] = nil
+	}
+	p.free = p.free[:0]
+}
+
+// WithRequestPool 将请求级对象池存入 context
+func WithRequestPool(ctx context.Context, p *RequestPool) context.Context {
+	return context.WithValue(ctx, constant.RequestPoolKey, p)
+}
+
+// RequestPoolFromContext 从 context 中获取请求级对象池
+func RequestPoolFromContext(ctx context.Context) (*RequestPool, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	p, ok := ctx.Value(constant.RequestPoolKey).(*RequestPool)
+	return p, ok && p != nil
+}
+
+// FromContext 返回 context 中的请求级对象池，不存在时返回 GlobalBytesPool
+func FromContext(ctx context.Context) BytesPool {
+	if p, ok := RequestPoolFromContext(ctx); ok {
+		return p
+	}
+	return GlobalBytesPool
+}
@@ -0,0 +1,98 @@
+package pool
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+)
+
+// countingPool 统计 Get/Put 次数的 BytesPool
+type countingPool struct {
+	gets, puts int
+}
+
+func (p *countingPool) Get() *bytes.Buffer { p.gets++; return new(bytes.Buffer) }
+func (p *countingPool) Put(*bytes.Buffer)  { p.puts++ }
+
+func TestRequestPool(t *testing.T) {
+	parent := &countingPool{}
+	p := NewRequestPool(parent)
+
+	// 同时持有的 Buffer 互不影响
+	a, b := p.Get(), p.Get()
+	if a == b {
+		t.Fatal("Get returned the same buffer twice")
+	}
+	a.WriteString("hello")
+	b.WriteString("world")
+	if a.String() != "hello" || b.String() != "world" {
+		t.Errorf("buffers are not isolated: a=%q b=%q", a.String(), b.String())
+	}
+
+	// Put 之后再 Get 复用本地 Buffer，且已 Reset
+	p.Put(a)
+	c := p.Get()
+	if c != a {
+		t.Error("Get should reuse the buffer put back in this request")
+	}
+	if c.Len() != 0 {
+		t.Errorf("reused buffer is dirty: %q", c.String())
+	}
+	if parent.gets != 2 {
+		t.Errorf("parent gets = %d, want 2", parent.gets)
+	}
+
+	// Release 将空闲的 Buffer 归还给 parent
+	p.Put(b)
+	p.Put(c)
+	p.Release()
+	if parent.puts != 2 {
+		t.Errorf("parent puts after Release = %d, want 2", parent.puts)
+	}
+	p.Release()
+	if parent.puts != 2 {
+		t.Errorf("second Release should not put again, puts = %d", parent.puts)
+	}
+}
+
+func TestRequestPool_Context(t *testing.T) {
+	if got := FromContext(context.Background()); got != GlobalBytesPool {
+		t.Error("FromContext without request pool should return GlobalBytesPool")
+	}
+
+	p := NewRequestPool(nil)
+	ctx := WithRequestPool(context.Background(), p)
+	if got, ok := RequestPoolFromContext(ctx); !ok || got != p {
+		t.Errorf("RequestPoolFromContext = (%p, %v), want (%p, true)", got, ok, p)
+	}
+	if got := FromContext(ctx); got != p {
+		t.Error("FromContext should return the request pool")
+	}
+}
+
+// simulateRequest 模拟一个请求内格式化多行日志
+func simulateRequest(p BytesPool) {
+	for i := 0; i < 32; i++ {
+		buf := p.Get()
+		fmt.Fprintf(buf, "INFO: handle request step=%d user=%s", i, "tom")
+		p.Put(buf)
+	}
+}
+
+func BenchmarkRequest_GlobalPool(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		simulateRequest(GlobalBytesPool)
+	}
+}
+
+func BenchmarkRequest_RequestPool(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		p := NewRequestPool(nil)
+		ctx := WithRequestPool(context.Background(), p)
+		simulateRequest(FromContext(ctx))
+		p.Release()
+	}
+}
@@ -0,0 +1,85 @@
+package ring
+
+import "sync"
+
+// Buffer 固定容量的环形缓冲区（FIFO），写满后新元素覆盖最旧的元素，并发安全
+// 适用于保留最近 N 条数据的场景
+type Buffer[T any] struct {
+	items []T
+	head  int // 最旧元素的下标
+	size  int
+	mutex sync.Mutex
+}
+
+// New 创建容量为 capacity 的环形缓冲区，capacity <= 0 时容量为 1
+func New[T any](capacity int) *Buffer[T] {
+	return &Buffer[T]{
+		items: make([]T, max(capacity, 1)),
+	}
+}
+
+// Push 写入元素，已满时覆盖最旧的元素
+func (rb *Buffer[T]) Push(item T) {
+	rb.mutex.Lock()
+	defer rb.mutex.Unlock()
+
+	tail := (rb.head + rb.size) % len(rb.items)
+	rb.items[tail] = item
+	if rb.size < len(rb.items) {
+		rb.size++
+		return
+	}
+	// 已满，最旧的元素被覆盖
+	rb.head = (rb.head + 1) % len(rb.items)
+}
+
+// PopOldest 取出并移除最旧的元素，为空时返回零值和 false
+func (rb *Buffer[T]) PopOldest() (T, bool) {
+	rb.mutex.Lock()
+	defer rb.mutex.Unlock()
+
+	var zero T
+	if rb.size == 0 {
+		return zero, false
+	}
+	item := rb.items[rb.head]
+	rb.items[rb.head] = zero // 释放引用，避免内存泄漏
+	rb.head = (rb.head + 1) % len(rb.items)
+	rb.size--
+	return item, true
+}
+
+// Len 返回当前元素个数
+func (rb *Buffer[T]) Len() int {
+	rb.mutex.Lock()
+	defer rb.mutex.Unlock()
+
+	return rb.size
+}
+
+// Cap 返回容量
+func (rb *Buffer[T]) Cap() int {
+	return len(rb.items)
+}
+
+// Slice 按从旧到新的顺序返回所有元素的拷贝
+func (rb *Buffer[T]) Slice() []T {
+	rb.mutex.Lock()
+	defer rb.mutex.Unlock()
+
+	result := make([]T, rb.size)
+	for i := 0; i < rb.size; i++ {
+		result[i] = rb.items[(rb.head+i)%len(rb.items)]
+	}
+	return result
+}
+
+// Reset 清空所有元素，容量不变
+func (rb *Buffer[T]) Reset() {
+	rb.mutex.Lock()
+	defer rb.mutex.Unlock()
+
+	clear(rb.items)
+	rb.head = 0
+	rb.size = 0
+}
@@ -0,0 +1,78 @@
+package ring
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBuffer(t *testing.T) {
+	t.Run("未满时按FIFO顺序", func(t *testing.T) {
+		rb := New[int](3)
+		rb.Push(1)
+		rb.Push(2)
+		if got := rb.Slice(); !reflect.DeepEqual(got, []int{1, 2}) {
+			t.Errorf("Slice() = %v, want [1 2]", got)
+		}
+		if rb.Len() != 2 || rb.Cap() != 3 {
+			t.Errorf("Len() = %d, Cap() = %d, want 2, 3", rb.Len(), rb.Cap())
+		}
+	})
+
+	t.Run("写满后覆盖最旧的元素", func(t *testing.T) {
+		rb := New[int](3)
+		for i := 1; i <= 5; i++ {
+			rb.Push(i)
+		}
+		if got := rb.Slice(); !reflect.DeepEqual(got, []int{3, 4, 5}) {
+			t.Errorf("Slice() = %v, want [3 4 5]", got)
+		}
+		if rb.Len() != 3 {
+			t.Errorf("Len() = %d, want 3", rb.Len())
+		}
+	})
+
+	t.Run("PopOldest按FIFO顺序取出", func(t *testing.T) {
+		rb := New[string](2)
+		rb.Push("a")
+		rb.Push("b")
+		rb.Push("c")
+
+		for _, want := range []string{"b", "c"} {
+			if got, ok := rb.PopOldest(); !ok || got != want {
+				t.Errorf("PopOldest() = (%q, %v), want (%q, true)", got, ok, want)
+			}
+		}
+		if got, ok := rb.PopOldest(); ok || got != "" {
+			t.Errorf("PopOldest() on empty = (%q, %v), want (\"\", false)", got, ok)
+		}
+
+		// 取空后可以继续写入
+		rb.Push("d")
+		if got := rb.Slice(); !reflect.DeepEqual(got, []string{"d"}) {
+			t.Errorf("Slice() = %v, want [d]", got)
+		}
+	})
+
+	t.Run("容量非法时为1", func(t *testing.T) {
+		rb := New[int](0)
+		rb.Push(1)
+		rb.Push(2)
+		if got := rb.Slice(); !reflect.DeepEqual(got, []int{2}) {
+			t.Errorf("Slice() = %v, want [2]", got)
+		}
+	})
+	t.Run("Reset清空元素", func(t *testing.T) {
+		rb := New[int](2)
+		rb.Push(1)
+		rb.Push(2)
+		rb.Push(3)
+		rb.Reset()
+		if rb.Len() != 0 || len(rb.Slice()) != 0 {
+			t.Errorf("after Reset Len() = %d, Slice() = %v, want empty", rb.Len(), rb.Slice())
+		}
+		rb.Push(4)
+		if got := rb.Slice(); !reflect.DeepEqual(got, []int{4}) {
+			t.Errorf("Slice() = %v, want [4]", got)
+		}
+	})
+}
@@ -21,8 +21,14 @@ type Producer interface {
 // 如 duration= 5分钟，则每5分钟产生一个新的value
 // 新生成的值会通过回调告知使用者，或者也可以通过Get方法读取到
 func NewProducer(duration time.Duration, producerFn func() interface{}) Producer {
+	return NewProducerInLocation(duration, time.Local, producerFn)
+}
+
+// NewProducerInLocation 创建一个按指定时区计算周期边界的生产者
+// 如 duration= 24小时，loc=Asia/Shanghai，则在上海时间每天0点产生新的value
+func NewProducerInLocation(duration time.Duration, loc *time.Location, producerFn func() interface{}) Producer {
 	p := &producer{
-		cron:         NewSimpleCron(duration),
+		cron:         NewSimpleCronInLocation(duration, loc),
 		producerFunc: producerFn,
 	}
 
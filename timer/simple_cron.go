@@ -13,8 +13,20 @@ import (
 //	1*time.Minute 等效于 unix 的 */1
 //	5*time.Minute 等效于 unix 的 */5
 func NewSimpleCron(duration time.Duration) *SimpleCron {
+	return NewSimpleCronInLocation(duration, time.Local)
+}
+
+// NewSimpleCronInLocation 创建一个按指定时区计算触发时间的定时任务管理器
+//
+//	如 duration=24*time.Hour, loc=Asia/Shanghai，将在上海时间每天0点触发
+//	loc 为 nil 时使用 time.Local
+func NewSimpleCronInLocation(duration time.Duration, loc *time.Location) *SimpleCron {
+	if loc == nil {
+		loc = time.Local
+	}
 	sc := &SimpleCron{
 		duration: duration,
+		location: loc,
 		stopCh:   make(chan struct{}),
 	}
 	_ = sc.start()
@@ -24,6 +36,7 @@ func NewSimpleCron(duration time.Duration) *SimpleCron {
 // SimpleCron 一个简单的定时任务管理器
 type SimpleCron struct {
 	duration time.Duration
+	location *time.Location
 	jobs     []func()
 
 	timer *time.Timer
@@ -136,8 +149,10 @@ func (sc *SimpleCron) Stop() {
 func (sc *SimpleCron) next() time.Duration {
 	// time.Now().UnixNano() 是相对1970年1月1日（UTC时区）经过的纳秒数
 	// 计算各时区相对于这个时间所经过的纳秒数 需要加上各时区相对于UTC时区的偏移量
-	_, offsetSec := nowFunc().Zone()
-	nowLocalTs := nowFunc().UnixNano() + int64(time.Duration(offsetSec)*time.Second)
+	// 时区由 location 决定，默认为 time.Local
+	now := nowFunc().In(sc.location)
+	_, offsetSec := now.Zone()
+	nowLocalTs := now.UnixNano() + int64(time.Duration(offsetSec)*time.Second)
 	next := int64(sc.duration) - nowLocalTs%int64(sc.duration)
 	return time.Duration(next)
 }
@@ -27,3 +27,23 @@ func TestSimpleCronStopCanBeCalledMultiTimes(t *testing.T) {
 	sc.Stop()
 }
 
+func TestSimpleCronNextInLocation(t *testing.T) {
+	oldNow := nowFunc
+	defer func() { nowFunc = oldNow }()
+
+	// UTC 15:59:00 即 UTC+8 的 23:59:00，距离 UTC+8 的0点还有1分钟
+	cst := time.FixedZone("CST", 8*3600)
+	nowFunc = func() time.Time {
+		return time.Date(2024, 1, 1, 15, 59, 0, 0, time.UTC)
+	}
+
+	sc := &SimpleCron{duration: 24 * time.Hour, location: cst}
+	if got := sc.next(); got != time.Minute {
+		t.Fatalf("next() in CST = %v, want %v", got, time.Minute)
+	}
+
+	sc = &SimpleCron{duration: 24 * time.Hour, location: time.UTC}
+	if got := sc.next(); got != 8*time.Hour+time.Minute {
+		t.Fatalf("next() in UTC = %v, want %v", got, 8*time.Hour+time.Minute)
+	}
+}
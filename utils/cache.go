@@ -0,0 +1,19 @@
+package utils
+
+// Cache 缓存的通用接口，LocalCache 与 RedisCache 均实现了该接口
+// 业务代码依赖 Cache 即可在单机缓存和多副本共享的缓存之间切换
+type Cache interface {
+	// Get 获取缓存，不存在或已过期时返回 false
+	Get(key string) (interface{}, bool)
+	// Set 设置缓存
+	Set(key string, data interface{})
+	// Delete 删除缓存
+	Delete(key string)
+	// GetOrSet 从缓存获取数据，如果不存在则执行函数获取并设置缓存，第二个返回值表示是否命中缓存
+	GetOrSet(key string, fn func() (interface{}, error)) (interface{}, bool, error)
+}
+
+var (
+	_ Cache = (*LocalCache)(nil)
+	_ Cache = (*RedisCache)(nil)
+)
@@ -1,43 +1,175 @@
 package utils
 
-import "sync"
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
 
-var panicHandler func(info interface{})
+// SafeGoer 持有独立 panic 处理器的 goroutine 启动器
+// 不同的库或模块可以各自创建 SafeGoer，互不覆盖对方的 panic 处理器
+// 包级的 SafeGo、CallbackGo、SetPanicHandler 等函数使用默认实例
+type SafeGoer struct {
+	handler atomic.Pointer[func(info interface{})]
+}
 
-// SetPanicHandler 统一将goroutine的panic管理起来
-func SetPanicHandler(hd func(info interface{})) {
-	panicHandler = hd
+var defaultSafeGoer = &SafeGoer{}
+
+// NewSafeGoer 创建使用 hd 处理 panic 的 SafeGoer，hd 为 nil 时 panic 被静默吞掉
+func NewSafeGoer(hd func(info interface{})) *SafeGoer {
+	s := &SafeGoer{}
+	s.SetPanicHandler(hd)
+	return s
 }
 
-// SafeGo 安全的使用goroutine
-func SafeGo(fn func()) {
+// SetPanicHandler 设置 panic 处理器，可与 Go/CallbackGo 并发调用
+func (s *SafeGoer) SetPanicHandler(hd func(info interface{})) {
+	if hd == nil {
+		s.handler.Store(nil)
+		return
+	}
+	s.handler.Store(&hd)
+}
+
+// Go 安全的使用goroutine，panic 交给 s 的处理器
+func (s *SafeGoer) Go(fn func()) {
 	go func() {
-		defer func() {
-			if err := recover(); err != nil {
-				if panicHandler != nil {
-					panicHandler(err)
-				}
-			}
-		}()
+		defer s.handlePanic()
 		fn()
 	}()
 }
 
-// CallbackGo 安全使用go的同时，额外的保证在goroutine执行结束后调用回调函数，即使panic也会出发回调
-func CallbackGo(fn func(), callback func()) {
+// CallbackGo 安全使用go的同时，额外的保证在goroutine执行结束后调用回调函数，即使panic也会触发回调
+func (s *SafeGoer) CallbackGo(fn func(), callback func()) {
 	go func() {
 		defer func() {
 			callback()
 			if err := recover(); err != nil {
-				if panicHandler != nil {
-					panicHandler(err)
-				}
+				s.handle(err)
 			}
 		}()
 		fn()
 	}()
 }
 
+// handlePanic recover 当前 goroutine 的 panic 并交给 s 的处理器，需直接通过 defer 调用
+func (s *SafeGoer) handlePanic() {
+	if err := recover(); err != nil {
+		s.handle(err)
+	}
+}
+
+// handle 将 panic 信息交给处理器，未设置处理器时忽略
+func (s *SafeGoer) handle(info interface{}) {
+	if hd := s.handler.Load(); hd != nil {
+		(*hd)(info)
+	}
+}
+
+// SetPanicHandler 统一将goroutine的panic管理起来
+func SetPanicHandler(hd func(info interface{})) {
+	defaultSafeGoer.SetPanicHandler(hd)
+}
+
+// SafeGo 安全的使用goroutine
+func SafeGo(fn func()) {
+	defaultSafeGoer.Go(fn)
+}
+
+// SafeGoDone 与 SafeGo 相同，但返回一个在 fn 执行结束（包括 panic 处理完成）后关闭的 channel
+// 调用方可以通过 select 等待执行结束，或配合超时使用
+func SafeGoDone(fn func()) <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		defer handlePanic()
+		fn()
+	}()
+	return done
+}
+
+// CallbackGo 安全使用go的同时，额外的保证在goroutine执行结束后调用回调函数，即使panic也会出发回调
+func CallbackGo(fn func(), callback func()) {
+	defaultSafeGoer.CallbackGo(fn, callback)
+}
+
+// Debounce 防抖，返回的 debounced 每次调用都会重新计时，只有在 d 时间内没有新的调用时才执行一次 fn
+// cancel 用于取消尚未执行的调用；fn 在独立的 goroutine 中执行，panic 会交给默认的 panic 处理器
+func Debounce(d time.Duration, fn func()) (debounced func(), cancel func()) {
+	var (
+		mu    sync.Mutex
+		timer *time.Timer
+	)
+
+	run := func() {
+		defer handlePanic()
+		fn()
+	}
+
+	debounced = func() {
+		mu.Lock()
+		defer mu.Unlock()
+		if timer != nil {
+			timer.Stop()
+		}
+		timer = time.AfterFunc(d, run)
+	}
+
+	cancel = func() {
+		mu.Lock()
+		defer mu.Unlock()
+		if timer != nil {
+			timer.Stop()
+			timer = nil
+		}
+	}
+	return debounced, cancel
+}
+
+// Throttle 节流，返回的函数首次调用时立即执行 fn，之后每个 d 时间窗口内最多执行一次，窗口内的其余调用直接丢弃
+// fn 在调用方 goroutine 中同步执行
+func Throttle(d time.Duration, fn func()) func() {
+	var (
+		mu      sync.Mutex
+		lastRun time.Time
+	)
+
+	return func() {
+		mu.Lock()
+		now := time.Now()
+		if !lastRun.IsZero() && now.Sub(lastRun) < d {
+			mu.Unlock()
+			return
+		}
+		lastRun = now
+		mu.Unlock()
+
+		fn()
+	}
+}
+
+// handlePanic recover 当前 goroutine 的 panic 并交给默认的 panic 处理器，需直接通过 defer 调用
+func handlePanic() {
+	if err := recover(); err != nil {
+		defaultSafeGoer.handle(err)
+	}
+}
+
+// Lazy 延迟初始化，返回的函数首次调用时执行 init 并缓存结果，之后直接返回缓存的结果
+// 并发首次调用时 init 只会执行一次，适用于开销较大的包级单例
+func Lazy[T any](init func() T) func() T {
+	var (
+		once  sync.Once
+		value T
+	)
+	return func() T {
+		once.Do(func() {
+			value = init()
+		})
+		return value
+	}
+}
+
 type OnceErr struct {
 	err  error
 	once sync.Once
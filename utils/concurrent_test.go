@@ -0,0 +1,188 @@
+package utils
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDebounce(t *testing.T) {
+	t.Run("连续调用只执行一次", func(t *testing.T) {
+		var calls atomic.Int32
+		debounced, cancel := Debounce(30*time.Millisecond, func() {
+			calls.Add(1)
+		})
+		defer cancel()
+
+		for i := 0; i < 20; i++ {
+			debounced()
+			time.Sleep(time.Millisecond)
+		}
+		time.Sleep(100 * time.Millisecond)
+
+		if got := calls.Load(); got != 1 {
+			t.Errorf("fn 执行次数应为 1，实际为 %d", got)
+		}
+	})
+
+	t.Run("cancel 取消待执行的调用", func(t *testing.T) {
+		var calls atomic.Int32
+		debounced, cancel := Debounce(20*time.Millisecond, func() {
+			calls.Add(1)
+		})
+
+		debounced()
+		cancel()
+		time.Sleep(50 * time.Millisecond)
+
+		if got := calls.Load(); got != 0 {
+			t.Errorf("取消后 fn 不应执行，实际执行 %d 次", got)
+		}
+	})
+}
+
+func TestThrottle(t *testing.T) {
+	var calls atomic.Int32
+	throttled := Throttle(200*time.Millisecond, func() {
+		calls.Add(1)
+	})
+
+	for i := 0; i < 100; i++ {
+		throttled()
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("同一窗口内 fn 执行次数应为 1，实际为 %d", got)
+	}
+
+	time.Sleep(250 * time.Millisecond)
+	for i := 0; i < 100; i++ {
+		throttled()
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("进入下一窗口后 fn 执行次数应为 2，实际为 %d", got)
+	}
+}
+
+func TestLazy(t *testing.T) {
+	var calls atomic.Int32
+	get := Lazy(func() *int {
+		calls.Add(1)
+		time.Sleep(10 * time.Millisecond)
+		v := 42
+		return &v
+	})
+
+	const callers = 50
+	results := make([]*int, callers)
+	var wg sync.WaitGroup
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = get()
+		}(i)
+	}
+	wg.Wait()
+
+	if got := calls.Load(); got != 1 {
+		t.Errorf("init 执行次数应为 1，实际为 %d", got)
+	}
+	for i, r := range results {
+		if r != results[0] || *r != 42 {
+			t.Fatalf("第 %d 个调用方得到的值不一致: %p(%v), want %p", i, r, *r, results[0])
+		}
+	}
+}
+
+func TestSafeGoDone(t *testing.T) {
+	t.Run("正常结束", func(t *testing.T) {
+		var ran atomic.Bool
+		done := SafeGoDone(func() {
+			time.Sleep(10 * time.Millisecond)
+			ran.Store(true)
+		})
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("done 应在 fn 结束后关闭")
+		}
+		if !ran.Load() {
+			t.Error("done 关闭时 fn 应已执行完毕")
+		}
+	})
+
+	t.Run("panic 后仍然关闭", func(t *testing.T) {
+		var handled atomic.Value
+		old := defaultSafeGoer.handler.Load()
+		SetPanicHandler(func(info interface{}) { handled.Store(info) })
+		defer defaultSafeGoer.handler.Store(old)
+
+		done := SafeGoDone(func() { panic("boom") })
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("fn panic 时 done 也应关闭")
+		}
+		if got := handled.Load(); got != "boom" {
+			t.Errorf("panicHandler 收到 %v, want boom", got)
+		}
+	})
+}
+
+func TestSafeGoer(t *testing.T) {
+	sinkA := make(chan interface{}, 1)
+	sinkB := make(chan interface{}, 1)
+	a := NewSafeGoer(func(info interface{}) { sinkA <- info })
+	b := NewSafeGoer(func(info interface{}) { sinkB <- info })
+
+	a.Go(func() { panic("from a") })
+	b.CallbackGo(func() { panic("from b") }, func() {})
+
+	for _, tc := range []struct {
+		name string
+		sink chan interface{}
+		want string
+	}{
+		{"a", sinkA, "from a"},
+		{"b", sinkB, "from b"},
+	} {
+		select {
+		case got := <-tc.sink:
+			if got != tc.want {
+				t.Errorf("SafeGoer %s 收到 %v, want %v", tc.name, got, tc.want)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("SafeGoer %s 未收到 panic", tc.name)
+		}
+	}
+
+	// 各自的 panic 不会路由到对方的处理器
+	select {
+	case got := <-sinkA:
+		t.Errorf("SafeGoer a 不应收到额外的 panic: %v", got)
+	case got := <-sinkB:
+		t.Errorf("SafeGoer b 不应收到额外的 panic: %v", got)
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+func TestSetPanicHandler_Concurrent(t *testing.T) {
+	old := defaultSafeGoer.handler.Load()
+	defer defaultSafeGoer.handler.Store(old)
+
+	// 并发设置处理器与启动 goroutine，配合 -race 检查数据竞争
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			SetPanicHandler(func(interface{}) {})
+		}()
+		go func() {
+			defer wg.Done()
+			<-SafeGoDone(func() { panic("boom") })
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,60 @@
+package utils
+
+import "reflect"
+
+// Diff 比较 old 和 new 的导出字段，返回发生变化的字段，格式为 字段名 -> [旧值, 新值]，可直接用于审计日志
+//
+//	嵌套的结构体展开比较，key 以 . 连接，如 Address.City
+//	指针字段会解引用后比较，一边为 nil 时整体视为变化
+//	没有导出字段的结构体（如 time.Time）、slice、map 等整体使用 reflect.DeepEqual 比较
+//	T 不是结构体（或结构体指针）时，两者不同则以空字符串为 key 返回整体的变化
+//	没有变化时返回空 map
+func Diff[T any](old, new T) map[string][2]any {
+	result := make(map[string][2]any)
+	diffValue(result, "", reflect.ValueOf(&old).Elem(), reflect.ValueOf(&new).Elem())
+	return result
+}
+
+func diffValue(result map[string][2]any, key string, a, b reflect.Value) {
+	if a.Kind() == reflect.Pointer {
+		if a.IsNil() || b.IsNil() {
+			if a.IsNil() != b.IsNil() {
+				result[key] = [2]any{a.Interface(), b.Interface()}
+			}
+			return
+		}
+		diffValue(result, key, a.Elem(), b.Elem())
+		return
+	}
+
+	if a.Kind() == reflect.Struct && hasExportedField(a.Type()) {
+		t := a.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			name := field.Name
+			if key != "" {
+				name = key + "." + name
+			}
+			diffValue(result, name, a.Field(i), b.Field(i))
+		}
+		return
+	}
+
+	av, bv := a.Interface(), b.Interface()
+	if !reflect.DeepEqual(av, bv) {
+		result[key] = [2]any{av, bv}
+	}
+}
+
+// hasExportedField 判断结构体是否有导出字段
+func hasExportedField(t reflect.Type) bool {
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).IsExported() {
+			return true
+		}
+	}
+	return false
+}
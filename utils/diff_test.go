@@ -0,0 +1,70 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDiff(t *testing.T) {
+	type address struct {
+		City   string
+		Street string
+	}
+	type user struct {
+		ID        int
+		Name      string
+		Tags      []string
+		Address   address
+		Manager   *address
+		UpdatedAt time.Time
+		password  string
+	}
+
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	old := user{
+		ID:        1,
+		Name:      "alice",
+		Tags:      []string{"a"},
+		Address:   address{City: "Beijing", Street: "Main"},
+		Manager:   &address{City: "Beijing"},
+		UpdatedAt: now,
+		password:  "old",
+	}
+	updated := old
+	updated.Name = "bob"
+	updated.Tags = []string{"a", "b"}
+	updated.Address.City = "Shanghai"
+	updated.Manager = &address{City: "Shenzhen"}
+	updated.UpdatedAt = now.Add(time.Hour)
+	updated.password = "new"
+
+	got := Diff(old, updated)
+	want := map[string][2]any{
+		"Name":         {"alice", "bob"},
+		"Tags":         {[]string{"a"}, []string{"a", "b"}},
+		"Address.City": {"Beijing", "Shanghai"},
+		"Manager.City": {"Beijing", "Shenzhen"},
+		"UpdatedAt":    {now, now.Add(time.Hour)},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Diff() = %v, want %v", got, want)
+	}
+
+	if got := Diff(old, old); len(got) != 0 {
+		t.Errorf("Diff() of equal values = %v, want empty", got)
+	}
+
+	// 结构体指针与 nil 指针字段
+	noManager := updated
+	noManager.Manager = nil
+	got = Diff(&updated, &noManager)
+	if len(got) != 1 || got["Manager"][1] != (*address)(nil) {
+		t.Errorf("Diff() = %v, want only Manager changed to nil", got)
+	}
+
+	// 非结构体整体比较
+	if got := Diff(1, 2); !reflect.DeepEqual(got, map[string][2]any{"": {1, 2}}) {
+		t.Errorf("Diff(1, 2) = %v", got)
+	}
+}
@@ -0,0 +1,46 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrAsyncPanic Async 执行的函数 panic 时，Await 返回的错误
+var ErrAsyncPanic = errors.New("async panic")
+
+// Future 异步计算的结果，由 Async 创建
+type Future[T any] struct {
+	done  chan struct{}
+	value T
+	err   error
+}
+
+// Async 在新的 goroutine 中执行 fn，返回可等待其结果的 Future
+// fn panic 时会被 recover 并转换为 ErrAsyncPanic 通过 Await 返回，同时交给默认的 panic 处理器
+func Async[T any](fn func() (T, error)) *Future[T] {
+	f := &Future[T]{done: make(chan struct{})}
+	go func() {
+		defer close(f.done)
+		defer func() {
+			if r := recover(); r != nil {
+				var zero T
+				f.value = zero
+				f.err = fmt.Errorf("%w: %v", ErrAsyncPanic, r)
+				defaultSafeGoer.handle(r)
+			}
+		}()
+		f.value, f.err = fn()
+	}()
+	return f
+}
+
+// Await 阻塞直到 fn 执行结束，返回其结果，可以多次调用
+func (f *Future[T]) Await() (T, error) {
+	<-f.done
+	return f.value, f.err
+}
+
+// Done 返回在 fn 执行结束后关闭的 channel，可配合 select 实现超时等待
+func (f *Future[T]) Done() <-chan struct{} {
+	return f.done
+}
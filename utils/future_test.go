@@ -0,0 +1,48 @@
+package utils
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestAsync(t *testing.T) {
+	t.Run("返回结果", func(t *testing.T) {
+		f := Async(func() (int, error) {
+			time.Sleep(10 * time.Millisecond)
+			return 42, nil
+		})
+
+		select {
+		case <-f.Done():
+		case <-time.After(time.Second):
+			t.Fatal("Done 应在 fn 结束后关闭")
+		}
+
+		for i := 0; i < 2; i++ {
+			got, err := f.Await()
+			if err != nil || got != 42 {
+				t.Errorf("Await() = %v, %v, want 42, nil", got, err)
+			}
+		}
+	})
+
+	t.Run("返回错误", func(t *testing.T) {
+		wantErr := errors.New("load error")
+		f := Async(func() (int, error) { return 0, wantErr })
+		if _, err := f.Await(); !errors.Is(err, wantErr) {
+			t.Errorf("Await() error = %v, want %v", err, wantErr)
+		}
+	})
+
+	t.Run("panic 转换为错误", func(t *testing.T) {
+		f := Async(func() (int, error) { panic("boom") })
+		got, err := f.Await()
+		if !errors.Is(err, ErrAsyncPanic) {
+			t.Fatalf("Await() error = %v, want ErrAsyncPanic", err)
+		}
+		if got != 0 {
+			t.Errorf("Await() value = %v, want 0", got)
+		}
+	})
+}
@@ -0,0 +1,144 @@
+package utils
+
+import (
+	"errors"
+	"slices"
+	"time"
+)
+
+// ErrHotKeyNoExpiry 缓存未设置过期时间时 AddHotKey 返回的错误，此时无需也无法按过期时间预刷新
+var ErrHotKeyNoExpiry = errors.New("hot key requires a cache with positive expiry")
+
+// hotKeyRefresher 单个热点 key 的后台预刷新任务
+type hotKeyRefresher struct {
+	stop chan struct{}
+	done chan struct{}
+}
+
+// AddHotKey 将 key 设为热点 key：缓存项过期前 ahead 时间在后台调用 fn 刷新，使读取方不会遇到缓存缺失
+// 未缓存或已过期时先同步加载一次，加载失败时返回错误且不注册；刷新与 GetOrSet 共用 singleflight，
+// 同一个 key 同时只有一个加载。刷新失败时保留原数据并按 ahead/4 的间隔重试，直到数据过期；
+// 过期后（或缓存项被删除、淘汰后）仍失败时重试间隔逐次翻倍，最长为过期时间，避免下游故障时频繁调用 fn
+// ahead <= 0 或不小于过期时间时使用过期时间的 1/10；重复添加同一个 key 会替换原有的刷新任务
+// 缓存的过期时间 <= 0 时返回 ErrHotKeyNoExpiry
+func (lc *LocalCache) AddHotKey(key string, ahead time.Duration, fn func() (interface{}, error)) error {
+	if lc.expire <= 0 {
+		return ErrHotKeyNoExpiry
+	}
+	if ahead <= 0 || ahead >= lc.expire {
+		ahead = lc.expire / 10
+	}
+
+	if _, exists := lc.Get(key); !exists {
+		if _, err, _ := lc.group.Do(key, func() (interface{}, error) {
+			return lc.loadAndSet(key, fn)
+		}); err != nil {
+			return err
+		}
+	}
+
+	r := &hotKeyRefresher{
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+
+	lc.hotMu.Lock()
+	if lc.hotKeys == nil {
+		lc.hotKeys = make(map[string]*hotKeyRefresher)
+	}
+	old := lc.hotKeys[key]
+	lc.hotKeys[key] = r
+	lc.hotMu.Unlock()
+
+	if old != nil {
+		old.close()
+	}
+
+	go lc.refreshHotKey(key, ahead, fn, r)
+	return nil
+}
+
+// RemoveHotKey 停止 key 的后台预刷新，已缓存的数据保留到正常过期
+func (lc *LocalCache) RemoveHotKey(key string) {
+	lc.hotMu.Lock()
+	r := lc.hotKeys[key]
+	delete(lc.hotKeys, key)
+	lc.hotMu.Unlock()
+
+	if r != nil {
+		r.close()
+	}
+}
+
+// StopHotKeys 停止所有热点 key 的后台预刷新，退出前调用
+func (lc *LocalCache) StopHotKeys() {
+	lc.hotMu.Lock()
+	refreshers := lc.hotKeys
+	lc.hotKeys = nil
+	lc.hotMu.Unlock()
+
+	for _, r := range refreshers {
+		r.close()
+	}
+}
+
+// HotKeys 返回正在预刷新的 key，按字典序排列
+func (lc *LocalCache) HotKeys() []string {
+	lc.hotMu.Lock()
+	defer lc.hotMu.Unlock()
+
+	keys := ArrayKeys(lc.hotKeys)
+	slices.Sort(keys)
+	return keys
+}
+
+// refreshHotKey 在缓存项过期前 ahead 时间刷新，刷新成功后按新的写入时间等待下一次刷新
+func (lc *LocalCache) refreshHotKey(key string, ahead time.Duration, fn func() (interface{}, error), r *hotKeyRefresher) {
+	defer close(r.done)
+
+	minRetry := max(ahead/4, time.Millisecond)
+	retry := minRetry
+	next, _ := lc.nextHotKeyRefresh(key, ahead)
+	timer := time.NewTimer(next)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-timer.C:
+			_, err, _ := lc.group.Do(key, func() (interface{}, error) {
+				return lc.loadAndSet(key, fn)
+			})
+			if err == nil {
+				retry = minRetry
+				next, _ = lc.nextHotKeyRefresh(key, ahead)
+				timer.Reset(next)
+				continue
+			}
+			if _, fresh := lc.nextHotKeyRefresh(key, ahead); !fresh {
+				// 数据已过期或不存在，退避重试
+				retry = min(retry*2, lc.expire)
+			}
+			timer.Reset(retry)
+		case <-r.stop:
+			return
+		}
+	}
+}
+
+// nextHotKeyRefresh 返回距离下一次刷新的时长，以及缓存项是否存在且未过期
+// 缓存项不存在（被删除或淘汰）时立即刷新
+func (lc *LocalCache) nextHotKeyRefresh(key string, ahead time.Duration) (time.Duration, bool) {
+	lc.mutex.RLock()
+	item, exists := lc.items[key]
+	lc.mutex.RUnlock()
+	if !exists {
+		return 0, false
+	}
+	return max(time.Until(item.Timestamp.Add(lc.expire-ahead)), 0), time.Since(item.Timestamp) < lc.expire
+}
+
+// close 停止刷新任务并等待其退出
+func (r *hotKeyRefresher) close() {
+	close(r.stop)
+	<-r.done
+}
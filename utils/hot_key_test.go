@@ -0,0 +1,195 @@
+package utils
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestLocalCache_AddHotKey(t *testing.T) {
+	t.Run("跨过期边界读取不缺失", func(t *testing.T) {
+		lc := NewLocalCache(50 * time.Millisecond)
+		defer lc.StopHotKeys()
+
+		var loads atomic.Int64
+		load := func() (interface{}, error) {
+			// 模拟较慢的加载，预刷新需要在过期前完成
+			time.Sleep(5 * time.Millisecond)
+			return loads.Add(1), nil
+		}
+		if err := lc.AddHotKey("hot", 20*time.Millisecond, load); err != nil {
+			t.Fatalf("AddHotKey() error = %v", err)
+		}
+
+		var misses atomic.Int64
+		var wg sync.WaitGroup
+		deadline := time.Now().Add(300 * time.Millisecond)
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for time.Now().Before(deadline) {
+					if _, ok := lc.Get("hot"); !ok {
+						misses.Add(1)
+					}
+					time.Sleep(100 * time.Microsecond)
+				}
+			}()
+		}
+		wg.Wait()
+
+		if n := misses.Load(); n != 0 {
+			t.Errorf("热点 key 缺失 %d 次，应为 0", n)
+		}
+		// 300ms 内至少跨过 5 个过期周期
+		if n := loads.Load(); n < 5 {
+			t.Errorf("加载次数为 %d，应至少为 5", n)
+		}
+	})
+
+	t.Run("与 GetOrSet 共用 singleflight", func(t *testing.T) {
+		lc := NewLocalCache(time.Hour)
+		defer lc.StopHotKeys()
+
+		var calls atomic.Int64
+		release := make(chan struct{})
+		load := func() (interface{}, error) {
+			calls.Add(1)
+			<-release
+			return "v", nil
+		}
+
+		done := make(chan error, 1)
+		go func() {
+			done <- lc.AddHotKey("hot", time.Minute, load)
+		}()
+		waitFor(time.Second, func() bool { return calls.Load() == 1 })
+
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, _, _ = lc.GetOrSet("hot", load)
+			}()
+		}
+		time.Sleep(10 * time.Millisecond)
+		close(release)
+		wg.Wait()
+
+		if err := <-done; err != nil {
+			t.Fatalf("AddHotKey() error = %v", err)
+		}
+		if n := calls.Load(); n != 1 {
+			t.Errorf("加载次数为 %d，应为 1", n)
+		}
+	})
+
+	t.Run("首次加载失败不注册", func(t *testing.T) {
+		lc := NewLocalCache(time.Hour)
+		errLoad := errors.New("load error")
+
+		err := lc.AddHotKey("hot", time.Minute, func() (interface{}, error) {
+			return nil, errLoad
+		})
+		if !errors.Is(err, errLoad) {
+			t.Errorf("AddHotKey() error = %v, want %v", err, errLoad)
+		}
+		if keys := lc.HotKeys(); len(keys) != 0 {
+			t.Errorf("HotKeys() = %v，应为空", keys)
+		}
+	})
+
+	t.Run("刷新失败保留原数据", func(t *testing.T) {
+		lc := NewLocalCache(60 * time.Millisecond)
+		defer lc.StopHotKeys()
+
+		var fail atomic.Bool
+		var attempts atomic.Int64
+		errLoad := errors.New("load error")
+		if err := lc.AddHotKey("hot", 40*time.Millisecond, func() (interface{}, error) {
+			attempts.Add(1)
+			if fail.Load() {
+				return nil, errLoad
+			}
+			return "v", nil
+		}); err != nil {
+			t.Fatalf("AddHotKey() error = %v", err)
+		}
+		fail.Store(true)
+
+		// 刷新从写入 20ms 后开始失败并重试，过期前数据仍可读取
+		time.Sleep(40 * time.Millisecond)
+		if data, ok := lc.Get("hot"); !ok || data != "v" {
+			t.Errorf("Get() = (%v, %v)，应返回原数据", data, ok)
+		}
+		if n := attempts.Load(); n < 2 {
+			t.Errorf("加载次数为 %d，刷新失败后应重试", n)
+		}
+	})
+
+	t.Run("过期后退避重试", func(t *testing.T) {
+		lc := NewLocalCache(20 * time.Millisecond)
+		defer lc.StopHotKeys()
+
+		var attempts atomic.Int64
+		if err := lc.AddHotKey("hot", 10*time.Millisecond, func() (interface{}, error) {
+			if attempts.Add(1) > 1 {
+				return nil, errors.New("load error")
+			}
+			return "v", nil
+		}); err != nil {
+			t.Fatalf("AddHotKey() error = %v", err)
+		}
+
+		// 不退避时按 2.5ms 间隔重试，200ms 内约 80 次；退避后间隔最长为过期时间 20ms
+		time.Sleep(200 * time.Millisecond)
+		if n := attempts.Load(); n > 30 {
+			t.Errorf("加载次数为 %d，过期后应退避重试", n)
+		}
+	})
+
+	t.Run("未设置过期时间", func(t *testing.T) {
+		lc := NewLocalCache(0)
+		calls := 0
+		err := lc.AddHotKey("hot", time.Millisecond, func() (interface{}, error) {
+			calls++
+			return "v", nil
+		})
+		if !errors.Is(err, ErrHotKeyNoExpiry) {
+			t.Errorf("AddHotKey() error = %v, want %v", err, ErrHotKeyNoExpiry)
+		}
+		if calls != 0 || len(lc.HotKeys()) != 0 {
+			t.Errorf("calls = %d, HotKeys() = %v，不应加载或注册", calls, lc.HotKeys())
+		}
+	})
+
+	t.Run("RemoveHotKey 停止刷新", func(t *testing.T) {
+		lc := NewLocalCache(20 * time.Millisecond)
+
+		var loads atomic.Int64
+		if err := lc.AddHotKey("hot", 10*time.Millisecond, func() (interface{}, error) {
+			return loads.Add(1), nil
+		}); err != nil {
+			t.Fatalf("AddHotKey() error = %v", err)
+		}
+		if keys := lc.HotKeys(); len(keys) != 1 || keys[0] != "hot" {
+			t.Errorf("HotKeys() = %v", keys)
+		}
+
+		lc.RemoveHotKey("hot")
+		n := loads.Load()
+		time.Sleep(50 * time.Millisecond)
+		if loads.Load() != n {
+			t.Errorf("RemoveHotKey 后仍在刷新，加载次数 %d -> %d", n, loads.Load())
+		}
+		if _, ok := lc.Get("hot"); ok {
+			t.Error("停止刷新后数据应正常过期")
+		}
+		if keys := lc.HotKeys(); len(keys) != 0 {
+			t.Errorf("HotKeys() = %v，应为空", keys)
+		}
+	})
+}
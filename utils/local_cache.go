@@ -1,19 +1,51 @@
 package utils
 
 import (
+	"bytes"
+	"cmp"
+	"container/list"
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"golang.org/x/sync/singleflight"
 )
 
+// ErrLoadPanic GetOrSet 等方法中加载函数 panic 时返回的错误
+var ErrLoadPanic = errors.New("cache load panic")
+
 // CacheItem 缓存项结构体
 type CacheItem struct {
 	Data      interface{} // 缓存数据
 	Timestamp time.Time   // 时间戳
+
+	elem *list.Element // 在 LRU 链表中的位置，未限制容量时为 nil
 }
 
+// CacheEntryAge 缓存项的 key 及其写入至今的时长
+type CacheEntryAge struct {
+	Key string
+	Age time.Duration
+}
+
+// ExpiryPolicy LocalCache 的缓存项过期后 GetOrSet / GetOrSetCtx 的行为
+type ExpiryPolicy int32
+
+const (
+	// StrictExpiry 过期数据立即失效，同步加载新值，加载失败时返回错误，默认值
+	StrictExpiry ExpiryPolicy = iota
+	// StaleWhileRevalidate 存在过期数据时直接返回过期数据，同时在后台加载新值，加载失败时保留过期数据
+	StaleWhileRevalidate
+	// StaleIfError 同步加载新值，仅在加载失败时返回过期数据，避免下游故障时缓存整体不可用
+	StaleIfError
+)
+
 // LocalCache 本地缓存结构体
 type LocalCache struct {
 	items map[string]*CacheItem
@@ -22,9 +54,17 @@ type LocalCache struct {
 	expire time.Duration // 缓存过期时间
 	group  singleflight.Group
 
+	maxEntries int        // 最大缓存条数，<=0 表示不限制
+	lru        *list.List // 按访问时间排序的 key，队头为最近访问
+
 	cleanupStop chan struct{}
 	cleanupDone chan struct{}
 	cleanupMu   sync.Mutex
+
+	policy atomic.Int32 // ExpiryPolicy
+
+	hotMu   sync.Mutex
+	hotKeys map[string]*hotKeyRefresher // 预刷新的热点 key
 }
 
 // NewLocalCache 创建新的本地缓存实例
@@ -35,8 +75,35 @@ func NewLocalCache(expire time.Duration) *LocalCache {
 	}
 }
 
+// NewLocalCacheWithMaxEntries 创建限制最大条数的本地缓存实例
+// 超过 maxEntries 时按 LRU 淘汰最久未访问的缓存项，maxEntries<=0 时等同于 NewLocalCache
+func NewLocalCacheWithMaxEntries(expire time.Duration, maxEntries int) *LocalCache {
+	lc := NewLocalCache(expire)
+	if maxEntries > 0 {
+		lc.maxEntries = maxEntries
+		lc.lru = list.New()
+	}
+	return lc
+}
+
+// SetExpiryPolicy 设置缓存项过期后 GetOrSet / GetOrSetCtx 的行为，默认为 StrictExpiry
+// 非 StrictExpiry 时过期数据不再在 Get 中删除，而是保留到被重新加载、淘汰或 CleanupExpired 清理；
+// 无论哪种策略，Get 都不会返回过期数据
+func (lc *LocalCache) SetExpiryPolicy(policy ExpiryPolicy) {
+	lc.policy.Store(int32(policy))
+}
+
+// ExpiryPolicy 返回当前的过期策略
+func (lc *LocalCache) ExpiryPolicy() ExpiryPolicy {
+	return ExpiryPolicy(lc.policy.Load())
+}
+
 // Get 从缓存获取数据
 func (lc *LocalCache) Get(key string) (interface{}, bool) {
+	if lc.lru != nil {
+		return lc.getAndTouch(key)
+	}
+
 	lc.mutex.RLock()
 	item, exists := lc.items[key]
 	if !exists {
@@ -50,6 +117,11 @@ func (lc *LocalCache) Get(key string) (interface{}, bool) {
 	}
 	lc.mutex.RUnlock()
 
+	// 允许返回过期数据的策略需要保留过期数据
+	if lc.ExpiryPolicy() != StrictExpiry {
+		return nil, false
+	}
+
 	// 读锁判断过期后，升级写锁并二次校验后删除，避免竞态误删。
 	lc.mutex.Lock()
 	defer lc.mutex.Unlock()
@@ -59,7 +131,7 @@ func (lc *LocalCache) Get(key string) (interface{}, bool) {
 		return nil, false
 	}
 	if time.Since(item.Timestamp) >= lc.expire {
-		delete(lc.items, key)
+		lc.deleteLocked(key)
 		return nil, false
 	}
 
@@ -68,15 +140,70 @@ func (lc *LocalCache) Get(key string) (interface{}, bool) {
 	return item.Data, true
 }
 
+// getAndTouch 限制容量时的 Get，命中后需要调整 LRU 顺序，因此全程持有写锁
+func (lc *LocalCache) getAndTouch(key string) (interface{}, bool) {
+	lc.mutex.Lock()
+	defer lc.mutex.Unlock()
+
+	item, exists := lc.items[key]
+	if !exists {
+		return nil, false
+	}
+	if time.Since(item.Timestamp) >= lc.expire {
+		if lc.ExpiryPolicy() == StrictExpiry {
+			lc.deleteLocked(key)
+		}
+		return nil, false
+	}
+
+	lc.lru.MoveToFront(item.elem)
+	return item.Data, true
+}
+
 // Set 设置缓存数据
 func (lc *LocalCache) Set(key string, data interface{}) {
 	lc.mutex.Lock()
 	defer lc.mutex.Unlock()
 
-	lc.items[key] = &CacheItem{
+	item := &CacheItem{
 		Data:      data,
 		Timestamp: time.Now(),
 	}
+
+	if lc.lru != nil {
+		if old, exists := lc.items[key]; exists {
+			item.elem = old.elem
+			lc.lru.MoveToFront(item.elem)
+		} else {
+			item.elem = lc.lru.PushFront(key)
+		}
+	}
+	lc.items[key] = item
+
+	lc.evictLocked()
+}
+
+// evictLocked 超出容量时淘汰最久未访问的缓存项，调用方需持有写锁
+func (lc *LocalCache) evictLocked() {
+	if lc.lru == nil {
+		return
+	}
+	for lc.lru.Len() > lc.maxEntries {
+		oldest := lc.lru.Back()
+		lc.deleteLocked(oldest.Value.(string))
+	}
+}
+
+// deleteLocked 删除缓存项并维护 LRU 链表，调用方需持有写锁
+func (lc *LocalCache) deleteLocked(key string) {
+	item, exists := lc.items[key]
+	if !exists {
+		return
+	}
+	if item.elem != nil {
+		lc.lru.Remove(item.elem)
+	}
+	delete(lc.items, key)
 }
 
 // Delete 删除缓存数据
@@ -84,7 +211,7 @@ func (lc *LocalCache) Delete(key string) {
 	lc.mutex.Lock()
 	defer lc.mutex.Unlock()
 
-	delete(lc.items, key)
+	lc.deleteLocked(key)
 }
 
 // Clear 清空所有缓存
@@ -93,6 +220,71 @@ func (lc *LocalCache) Clear() {
 	defer lc.mutex.Unlock()
 
 	lc.items = make(map[string]*CacheItem)
+	if lc.lru != nil {
+		lc.lru.Init()
+	}
+}
+
+// Len 返回未过期的缓存条数
+func (lc *LocalCache) Len() int {
+	lc.mutex.RLock()
+	defer lc.mutex.RUnlock()
+
+	n := 0
+	for _, item := range lc.items {
+		if time.Since(item.Timestamp) < lc.expire {
+			n++
+		}
+	}
+	return n
+}
+
+// Keys 返回未过期缓存的 key，按字典序排列
+func (lc *LocalCache) Keys() []string {
+	lc.mutex.RLock()
+	defer lc.mutex.RUnlock()
+
+	keys := make([]string, 0, len(lc.items))
+	for key, item := range lc.items {
+		if time.Since(item.Timestamp) < lc.expire {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// Items 返回未过期缓存数据的快照，修改返回的 map 不会影响缓存
+func (lc *LocalCache) Items() map[string]interface{} {
+	lc.mutex.RLock()
+	defer lc.mutex.RUnlock()
+
+	items := make(map[string]interface{}, len(lc.items))
+	for key, item := range lc.items {
+		if time.Since(item.Timestamp) < lc.expire {
+			items[key] = item.Data
+		}
+	}
+	return items
+}
+
+// EntriesByAge 返回未过期的缓存项，按写入时间从旧到新排列，可用于排查缓存内容或手动清理
+func (lc *LocalCache) EntriesByAge() []CacheEntryAge {
+	now := time.Now()
+
+	lc.mutex.RLock()
+	entries := make([]CacheEntryAge, 0, len(lc.items))
+	for key, item := range lc.items {
+		if age := now.Sub(item.Timestamp); age < lc.expire {
+			entries = append(entries, CacheEntryAge{Key: key, Age: age})
+		}
+	}
+	lc.mutex.RUnlock()
+
+	slices.SortFunc(entries, func(a, b CacheEntryAge) int {
+		return cmp.Compare(b.Age, a.Age)
+	})
+	return entries
 }
 
 // CleanupExpired 批量清理已过期的缓存项，返回清理数量。
@@ -105,7 +297,7 @@ func (lc *LocalCache) CleanupExpired() int {
 
 	for key, item := range lc.items {
 		if now.Sub(item.Timestamp) >= lc.expire {
-			delete(lc.items, key)
+			lc.deleteLocked(key)
 			removed++
 		}
 	}
@@ -169,28 +361,123 @@ func (lc *LocalCache) StopAutoCleanup() {
 }
 
 // GetOrSet 从缓存获取数据，如果不存在则执行函数获取并设置缓存
+// 缓存项过期后的行为由 ExpiryPolicy 决定，返回过期数据时 fromCache 为 true
 func (lc *LocalCache) GetOrSet(key string, fn func() (interface{}, error)) (interface{}, bool, error) {
 	if data, exists := lc.Get(key); exists {
 		return data, true, nil
 	}
+	if data, ok := lc.staleWhileRevalidate(key, fn); ok {
+		return data, true, nil
+	}
 
 	// 使用 singleflight 防止缓存击穿;如果重复执行,只有一个会真正执行,结束后返回值会copy到其他携程
 	result, err, _ := lc.group.Do(key, func() (interface{}, error) {
-		// 执行函数获取数据
-		data, err := fn()
-		if err != nil {
-			return nil, err
+		return lc.loadAndSet(key, fn)
+	})
+	if err != nil {
+		if data, ok := lc.staleIfError(key); ok {
+			return data, true, nil
 		}
+	}
+
+	return result, false, err
+}
+
+// getStale 返回缓存项的数据，不论是否过期
+func (lc *LocalCache) getStale(key string) (interface{}, bool) {
+	lc.mutex.RLock()
+	defer lc.mutex.RUnlock()
+
+	item, exists := lc.items[key]
+	if !exists {
+		return nil, false
+	}
+	return item.Data, true
+}
 
-		// 设置缓存
-		lc.Set(key, data)
-		return data, nil
+// staleWhileRevalidate 策略为 StaleWhileRevalidate 且存在过期数据时，在后台加载新值并返回过期数据
+// 后台加载同样经过 singleflight，同一个 key 同时只有一个加载
+func (lc *LocalCache) staleWhileRevalidate(key string, fn func() (interface{}, error)) (interface{}, bool) {
+	if lc.ExpiryPolicy() != StaleWhileRevalidate {
+		return nil, false
+	}
+	data, ok := lc.getStale(key)
+	if !ok {
+		return nil, false
+	}
+	// DoChan 的结果 channel 带缓冲，无需读取
+	lc.group.DoChan(key, func() (interface{}, error) {
+		return lc.loadAndSet(key, fn)
 	})
+	return data, true
+}
 
-	return result, false, err
+// staleIfError 策略为 StaleIfError 时返回过期数据，用于加载失败后的兜底
+func (lc *LocalCache) staleIfError(key string) (interface{}, bool) {
+	if lc.ExpiryPolicy() != StaleIfError {
+		return nil, false
+	}
+	return lc.getStale(key)
+}
+
+// loadAndSet 执行函数获取数据并设置缓存
+// fn panic 时会被 recover 并转换为 ErrLoadPanic，避免 panic 传播给任意一个等待者，且不会写入缓存
+func (lc *LocalCache) loadAndSet(key string, fn func() (interface{}, error)) (data interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			data = nil
+			err = fmt.Errorf("%w: key=%q, %v", ErrLoadPanic, key, r)
+		}
+	}()
+
+	data, err = fn()
+	if err != nil {
+		return nil, err
+	}
+
+	lc.Set(key, data)
+	return data, nil
+}
+
+// GetOrSetCtx 带 context 的 GetOrSet
+// 同一个 key 的并发加载仍然只会执行一次 fn；若调用方的 ctx 先结束，将直接返回 ctx.Err()，
+// 不会阻塞等待其他调用方发起的加载。fn 收到的 ctx 不会随单个调用方取消，避免影响其他等待者。
+func (lc *LocalCache) GetOrSetCtx(ctx context.Context, key string, fn func(context.Context) (interface{}, error)) (interface{}, bool, error) {
+	if data, exists := lc.Get(key); exists {
+		return data, true, nil
+	}
+
+	loadCtx := context.WithoutCancel(ctx)
+	load := func() (interface{}, error) {
+		return fn(loadCtx)
+	}
+	if data, ok := lc.staleWhileRevalidate(key, load); ok {
+		return data, true, nil
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, false, err
+	}
+
+	ch := lc.group.DoChan(key, func() (interface{}, error) {
+		return lc.loadAndSet(key, load)
+	})
+
+	select {
+	case res := <-ch:
+		if res.Err != nil {
+			if data, ok := lc.staleIfError(key); ok {
+				return data, true, nil
+			}
+		}
+		return res.Val, false, res.Err
+	case <-ctx.Done():
+		return nil, false, ctx.Err()
+	}
 }
 
 // GenerateCacheKey 生成缓存key
+// json.Number 按原始文本输出，不会转为 float64 损失精度；map 的 key 按字典序输出，顺序不影响结果
 func GenerateCacheKey(v interface{}) (string, error) {
 	jsonData, err := json.Marshal(v)
 	if err != nil {
@@ -198,3 +485,19 @@ func GenerateCacheKey(v interface{}) (string, error) {
 	}
 	return string(jsonData), nil
 }
+
+// GenerateCacheKeyFromJSON 由 JSON 数据（如请求体）生成缓存key
+// 解码时使用 UseNumber 保留数字的原始文本，避免大整数 ID 被转为 float64 后不同 ID 生成相同的 key
+func GenerateCacheKeyFromJSON(data []byte) (string, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var v interface{}
+	if err := decoder.Decode(&v); err != nil {
+		return "", err
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return "", errors.New("generate cache key: unexpected data after top-level JSON value")
+	}
+	return GenerateCacheKey(v)
+}
@@ -1,7 +1,10 @@
 package utils
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
+	"reflect"
 	"sync"
 	"testing"
 	"time"
@@ -197,6 +200,71 @@ func TestLocalCache_CleanupExpired(t *testing.T) {
 	})
 }
 
+func TestLocalCache_Inspect(t *testing.T) {
+	cache := NewLocalCache(time.Minute)
+	cache.Set("alive-2", 2)
+	cache.Set("alive-1", 1)
+	cache.Set("expired-1", 3)
+	cache.Set("expired-2", 4)
+
+	// 直接修改时间戳模拟过期
+	cache.mutex.Lock()
+	cache.items["expired-1"].Timestamp = time.Now().Add(-2 * time.Minute)
+	cache.items["expired-2"].Timestamp = time.Now().Add(-2 * time.Minute)
+	cache.mutex.Unlock()
+
+	if got := cache.Len(); got != 2 {
+		t.Errorf("Len() = %d, want 2", got)
+	}
+	if got, want := cache.Keys(), []string{"alive-1", "alive-2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Keys() = %v, want %v", got, want)
+	}
+
+	items := cache.Items()
+	if want := map[string]interface{}{"alive-1": 1, "alive-2": 2}; !reflect.DeepEqual(items, want) {
+		t.Errorf("Items() = %v, want %v", items, want)
+	}
+
+	// 修改快照不影响缓存
+	items["alive-1"] = 100
+	delete(items, "alive-2")
+	if v, ok := cache.Get("alive-1"); !ok || v != 1 {
+		t.Errorf("修改快照不应影响缓存，got=(%v,%v)", v, ok)
+	}
+	if _, ok := cache.Get("alive-2"); !ok {
+		t.Error("删除快照中的 key 不应影响缓存")
+	}
+}
+
+func TestLocalCache_EntriesByAge(t *testing.T) {
+	cache := NewLocalCache(time.Minute)
+	now := time.Now()
+	ages := map[string]time.Duration{
+		"newest":  time.Second,
+		"oldest":  30 * time.Second,
+		"middle":  10 * time.Second,
+		"expired": 2 * time.Minute,
+	}
+	for key, age := range ages {
+		cache.Set(key, key)
+		cache.mutex.Lock()
+		cache.items[key].Timestamp = now.Add(-age)
+		cache.mutex.Unlock()
+	}
+
+	entries := cache.EntriesByAge()
+	keys := make([]string, 0, len(entries))
+	for _, e := range entries {
+		keys = append(keys, e.Key)
+		if e.Age < ages[e.Key] || e.Age > ages[e.Key]+time.Second {
+			t.Errorf("entry %q age = %v, want about %v", e.Key, e.Age, ages[e.Key])
+		}
+	}
+	if want := []string{"oldest", "middle", "newest"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("EntriesByAge() keys = %v, want %v", keys, want)
+	}
+}
+
 func TestLocalCache_AutoCleanup(t *testing.T) {
 	t.Run("后台定时清理过期缓存", func(t *testing.T) {
 		cache := NewLocalCache(15 * time.Millisecond)
@@ -212,6 +280,43 @@ func TestLocalCache_AutoCleanup(t *testing.T) {
 	})
 }
 
+func TestLocalCache_MaxEntries(t *testing.T) {
+	t.Run("超过容量按 LRU 淘汰", func(t *testing.T) {
+		cache := NewLocalCacheWithMaxEntries(time.Hour, 2)
+		cache.Set("k1", "v1")
+		cache.Set("k2", "v2")
+		_, _ = cache.Get("k1")
+		cache.Set("k3", "v3")
+
+		if _, ok := cache.Get("k2"); ok {
+			t.Error("k2 最久未访问，应已被淘汰")
+		}
+		if _, ok := cache.Get("k1"); !ok {
+			t.Error("k1 不应被淘汰")
+		}
+		if _, ok := cache.Get("k3"); !ok {
+			t.Error("k3 不应被淘汰")
+		}
+		if len(cache.items) != 2 || cache.lru.Len() != 2 {
+			t.Errorf("缓存数量应为 2，实际为 items=%d lru=%d", len(cache.items), cache.lru.Len())
+		}
+	})
+
+	t.Run("删除与清空同步维护 LRU", func(t *testing.T) {
+		cache := NewLocalCacheWithMaxEntries(time.Hour, 3)
+		cache.Set("k1", "v1")
+		cache.Set("k2", "v2")
+		cache.Delete("k1")
+		if cache.lru.Len() != 1 {
+			t.Errorf("删除后 LRU 长度应为 1，实际为 %d", cache.lru.Len())
+		}
+		cache.Clear()
+		if cache.lru.Len() != 0 {
+			t.Errorf("清空后 LRU 长度应为 0，实际为 %d", cache.lru.Len())
+		}
+	})
+}
+
 func TestLocalCache_GetOrSet(t *testing.T) {
 	t.Run("缓存存在时直接返回", func(t *testing.T) {
 		cache := NewLocalCache(time.Hour)
@@ -420,6 +525,121 @@ func TestLocalCache_GetOrSet_Concurrent(t *testing.T) {
 	})
 }
 
+func TestLocalCache_GetOrSet_Panic(t *testing.T) {
+	t.Run("加载函数 panic 时所有调用方收到错误且不缓存", func(t *testing.T) {
+		cache := NewLocalCache(time.Hour)
+		key := "panic_key"
+		var wg sync.WaitGroup
+		concurrency := 10
+		errs := make(chan error, concurrency)
+
+		wg.Add(concurrency)
+		for i := 0; i < concurrency; i++ {
+			go func() {
+				defer wg.Done()
+				_, _, err := cache.GetOrSet(key, func() (interface{}, error) {
+					time.Sleep(10 * time.Millisecond)
+					panic("load failed")
+				})
+				errs <- err
+			}()
+		}
+		wg.Wait()
+		close(errs)
+
+		for err := range errs {
+			if !errors.Is(err, ErrLoadPanic) {
+				t.Errorf("错误应为 ErrLoadPanic，实际为 %v", err)
+			}
+		}
+		if _, ok := cache.Get(key); ok {
+			t.Error("panic 时不应写入缓存")
+		}
+	})
+
+	t.Run("GetOrSetCtx 加载函数 panic", func(t *testing.T) {
+		cache := NewLocalCache(time.Hour)
+		_, _, err := cache.GetOrSetCtx(context.Background(), "key", func(context.Context) (interface{}, error) {
+			panic("load failed")
+		})
+		if !errors.Is(err, ErrLoadPanic) {
+			t.Errorf("错误应为 ErrLoadPanic，实际为 %v", err)
+		}
+	})
+}
+
+func TestLocalCache_GetOrSetCtx(t *testing.T) {
+	t.Run("缓存存在时直接返回", func(t *testing.T) {
+		cache := NewLocalCache(time.Hour)
+		cache.Set("key", "value")
+
+		result, fromCache, err := cache.GetOrSetCtx(context.Background(), "key", func(context.Context) (interface{}, error) {
+			return "new_value", nil
+		})
+		if err != nil || !fromCache || result != "value" {
+			t.Errorf("got=(%v,%v,%v)，应从缓存返回 value", result, fromCache, err)
+		}
+	})
+
+	t.Run("一个调用方取消，另一个调用方完成加载", func(t *testing.T) {
+		cache := NewLocalCache(time.Hour)
+		key := "ctx_key"
+		started := make(chan struct{})
+		release := make(chan struct{})
+		var callCount int
+		var mu sync.Mutex
+
+		loader := func(ctx context.Context) (interface{}, error) {
+			mu.Lock()
+			callCount++
+			mu.Unlock()
+			close(started)
+			<-release
+			return "loaded", ctx.Err()
+		}
+
+		type result struct {
+			val interface{}
+			err error
+		}
+		firstDone := make(chan result, 1)
+		go func() {
+			val, _, err := cache.GetOrSetCtx(context.Background(), key, loader)
+			firstDone <- result{val, err}
+		}()
+		<-started
+
+		ctx, cancel := context.WithCancel(context.Background())
+		secondDone := make(chan result, 1)
+		go func() {
+			val, _, err := cache.GetOrSetCtx(ctx, key, loader)
+			secondDone <- result{val, err}
+		}()
+		cancel()
+
+		select {
+		case r := <-secondDone:
+			if !errors.Is(r.err, context.Canceled) {
+				t.Fatalf("取消的调用方应返回 context.Canceled，实际为 %v", r.err)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("取消的调用方不应阻塞等待其他调用方的加载")
+		}
+
+		close(release)
+		r := <-firstDone
+		if r.err != nil || r.val != "loaded" {
+			t.Fatalf("未取消的调用方应拿到加载结果，got=(%v,%v)", r.val, r.err)
+		}
+		if callCount != 1 {
+			t.Errorf("函数调用次数应为 1，实际为 %d", callCount)
+		}
+		if val, ok := cache.Get(key); !ok || val != "loaded" {
+			t.Errorf("加载结果应已写入缓存，got=(%v,%v)", val, ok)
+		}
+	})
+}
+
 func TestGenerateCacheKey(t *testing.T) {
 	t.Run("生成字符串缓存键", func(t *testing.T) {
 		input := "test_string"
@@ -471,6 +691,20 @@ func TestGenerateCacheKey(t *testing.T) {
 		}
 	})
 
+	t.Run("json.Number 保留大整数精度", func(t *testing.T) {
+		// 2^53 + 1，转为 float64 后会变成 9007199254740992
+		input := map[string]any{"id": json.Number("9007199254740993")}
+
+		key, err := GenerateCacheKey(input)
+
+		if err != nil {
+			t.Errorf("不应有错误，实际为 %v", err)
+		}
+		if want := `{"id":9007199254740993}`; key != want {
+			t.Errorf("缓存键应为 %s，实际为 %s", want, key)
+		}
+	})
+
 	t.Run("生成无法序列化的数据缓存键", func(t *testing.T) {
 		// 函数类型无法被 JSON 序列化
 		input := func() {}
@@ -482,3 +716,133 @@ func TestGenerateCacheKey(t *testing.T) {
 		}
 	})
 }
+
+func TestGenerateCacheKeyFromJSON(t *testing.T) {
+	t.Run("大整数保留完整精度", func(t *testing.T) {
+		key1, err := GenerateCacheKeyFromJSON([]byte(`{"id": 9007199254740993, "name": "a"}`))
+		if err != nil {
+			t.Fatalf("不应有错误，实际为 %v", err)
+		}
+		key2, err := GenerateCacheKeyFromJSON([]byte(`{"id": 9007199254740992, "name": "a"}`))
+		if err != nil {
+			t.Fatalf("不应有错误，实际为 %v", err)
+		}
+
+		if want := `{"id":9007199254740993,"name":"a"}`; key1 != want {
+			t.Errorf("缓存键应为 %s，实际为 %s", want, key1)
+		}
+		if key1 == key2 {
+			t.Errorf("不同 ID 的缓存键不应相同，均为 %s", key1)
+		}
+	})
+
+	t.Run("字段顺序不影响缓存键", func(t *testing.T) {
+		key1, _ := GenerateCacheKeyFromJSON([]byte(`{"b": [1, 2.5], "a": {"y": 1, "x": 2}}`))
+		key2, _ := GenerateCacheKeyFromJSON([]byte(`{"a": {"x": 2, "y": 1}, "b": [1, 2.5]}`))
+
+		if key1 != key2 {
+			t.Errorf("缓存键应相同，实际为 %s 和 %s", key1, key2)
+		}
+	})
+
+	t.Run("非法数据返回错误", func(t *testing.T) {
+		for _, data := range []string{`{"id":`, `{"id": 1} {}`, ``} {
+			if _, err := GenerateCacheKeyFromJSON([]byte(data)); err == nil {
+				t.Errorf("GenerateCacheKeyFromJSON(%q) 应有错误", data)
+			}
+		}
+	})
+}
+
+func TestLocalCache_ExpiryPolicy(t *testing.T) {
+	const expire = 20 * time.Millisecond
+	errLoad := errors.New("load failed")
+	ok := func(v string) func() (interface{}, error) {
+		return func() (interface{}, error) { return v, nil }
+	}
+	fail := func() (interface{}, error) { return nil, errLoad }
+
+	// newExpired 创建一个已写入 old 且已过期的缓存
+	newExpired := func(policy ExpiryPolicy) *LocalCache {
+		cache := NewLocalCache(expire)
+		cache.SetExpiryPolicy(policy)
+		cache.Set("key", "old")
+		time.Sleep(expire + 5*time.Millisecond)
+		return cache
+	}
+
+	t.Run("StrictExpiry 加载成功", func(t *testing.T) {
+		cache := newExpired(StrictExpiry)
+		val, fromCache, err := cache.GetOrSet("key", ok("new"))
+		if err != nil || fromCache || val != "new" {
+			t.Errorf("GetOrSet = (%v, %v, %v), want (new, false, nil)", val, fromCache, err)
+		}
+	})
+
+	t.Run("StrictExpiry 加载失败返回错误", func(t *testing.T) {
+		cache := newExpired(StrictExpiry)
+		val, fromCache, err := cache.GetOrSet("key", fail)
+		if !errors.Is(err, errLoad) || fromCache || val != nil {
+			t.Errorf("GetOrSet = (%v, %v, %v), want (nil, false, %v)", val, fromCache, err, errLoad)
+		}
+	})
+
+	t.Run("StaleWhileRevalidate 返回过期数据并在后台刷新", func(t *testing.T) {
+		cache := newExpired(StaleWhileRevalidate)
+		if val, ok := cache.Get("key"); ok {
+			t.Errorf("Get 不应返回过期数据，got=%v", val)
+		}
+
+		loaded := make(chan struct{})
+		val, fromCache, err := cache.GetOrSet("key", func() (interface{}, error) {
+			defer close(loaded)
+			return "new", nil
+		})
+		if err != nil || !fromCache || val != "old" {
+			t.Errorf("GetOrSet = (%v, %v, %v), want (old, true, nil)", val, fromCache, err)
+		}
+
+		<-loaded
+		if !waitFor(time.Second, func() bool { v, ok := cache.Get("key"); return ok && v == "new" }) {
+			t.Error("后台刷新后应返回新值")
+		}
+	})
+
+	t.Run("StaleWhileRevalidate 刷新失败保留过期数据", func(t *testing.T) {
+		cache := newExpired(StaleWhileRevalidate)
+		for i := 0; i < 2; i++ {
+			val, fromCache, err := cache.GetOrSet("key", fail)
+			if err != nil || !fromCache || val != "old" {
+				t.Errorf("第 %d 次 GetOrSet = (%v, %v, %v), want (old, true, nil)", i+1, val, fromCache, err)
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	})
+
+	t.Run("StaleIfError 加载成功返回新值", func(t *testing.T) {
+		cache := newExpired(StaleIfError)
+		val, fromCache, err := cache.GetOrSet("key", ok("new"))
+		if err != nil || fromCache || val != "new" {
+			t.Errorf("GetOrSet = (%v, %v, %v), want (new, false, nil)", val, fromCache, err)
+		}
+	})
+
+	t.Run("StaleIfError 加载失败返回过期数据", func(t *testing.T) {
+		cache := newExpired(StaleIfError)
+		val, fromCache, err := cache.GetOrSet("key", fail)
+		if err != nil || !fromCache || val != "old" {
+			t.Errorf("GetOrSet = (%v, %v, %v), want (old, true, nil)", val, fromCache, err)
+		}
+		val, fromCache, err = cache.GetOrSetCtx(context.Background(), "key", func(context.Context) (interface{}, error) {
+			return nil, errLoad
+		})
+		if err != nil || !fromCache || val != "old" {
+			t.Errorf("GetOrSetCtx = (%v, %v, %v), want (old, true, nil)", val, fromCache, err)
+		}
+
+		// 没有过期数据时仍返回错误
+		if _, _, err = cache.GetOrSet("missing", fail); !errors.Is(err, errLoad) {
+			t.Errorf("GetOrSet(missing) err = %v, want %v", err, errLoad)
+		}
+	})
+}
@@ -31,3 +31,70 @@ func ArrayValues[K comparable, V any](m map[K]V) []V {
 	}
 	return values
 }
+
+// MergeMaps 合并多个 map，key 冲突时后面的覆盖前面的，返回新的 map，不修改入参
+func MergeMaps[K comparable, V any](maps ...map[K]V) map[K]V {
+	size := 0
+	for _, m := range maps {
+		size += len(m)
+	}
+	result := make(map[K]V, size)
+	for _, m := range maps {
+		for k, v := range m {
+			result[k] = v
+		}
+	}
+	return result
+}
+
+// MergeWith 合并 a 和 b，两者都存在的 key 由 resolve 决定合并后的值，返回新的 map，不修改入参
+// 如合并两个计数器时 resolve 可返回 av + bv
+func MergeWith[K comparable, V any](a, b map[K]V, resolve func(key K, av, bv V) V) map[K]V {
+	result := make(map[K]V, len(a)+len(b))
+	for k, v := range a {
+		result[k] = v
+	}
+	for k, bv := range b {
+		if av, exists := result[k]; exists {
+			result[k] = resolve(k, av, bv)
+			continue
+		}
+		result[k] = bv
+	}
+	return result
+}
+
+// FilterMap 过滤 map，返回 f 为 true 的键值对组成的新 map，不修改入参
+func FilterMap[K comparable, V any](m map[K]V, f func(K, V) bool) map[K]V {
+	result := make(map[K]V)
+	for k, v := range m {
+		if f(k, v) {
+			result[k] = v
+		}
+	}
+	return result
+}
+
+// MapValues 转换 map 的值，返回新的 map，不修改入参
+func MapValues[K comparable, V any, U any](m map[K]V, f func(V) U) map[K]U {
+	result := make(map[K]U, len(m))
+	for k, v := range m {
+		result[k] = f(v)
+	}
+	return result
+}
+
+// GroupAndAggregate 按 keyFunc 分组，并对每组元素调用 agg 得到聚合结果，返回 key 到聚合结果的 map
+// 每组元素保持在 data 中的相对顺序，如按用户统计交易金额时 agg 可返回 Sum 的结果
+func GroupAndAggregate[T any, K comparable, R any](data []T, keyFunc func(T) K, agg func([]T) R) map[K]R {
+	groups := make(map[K][]T)
+	for _, item := range data {
+		key := keyFunc(item)
+		groups[key] = append(groups[key], item)
+	}
+	result := make(map[K]R, len(groups))
+	for key, items := range groups {
+		result[key] = agg(items)
+	}
+	return result
+}
@@ -138,3 +138,114 @@ func TestMapColumn(t *testing.T) {
 		})
 	}
 }
+
+func TestMergeMaps(t *testing.T) {
+	a := map[string]int{"a": 1, "b": 1}
+	b := map[string]int{"b": 2, "c": 2}
+	c := map[string]int{"c": 3, "d": 3}
+
+	got := MergeMaps(a, nil, b, c)
+	want := map[string]int{"a": 1, "b": 2, "c": 3, "d": 3}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("MergeMaps() = %v, want %v", got, want)
+	}
+
+	// 入参不应被修改
+	if !reflect.DeepEqual(a, map[string]int{"a": 1, "b": 1}) {
+		t.Errorf("input map modified: %v", a)
+	}
+
+	if got := MergeMaps[string, int](); len(got) != 0 || got == nil {
+		t.Errorf("MergeMaps() with no args = %v, want empty map", got)
+	}
+}
+
+func TestMergeWith(t *testing.T) {
+	a := map[string]int{"get": 3, "post": 1}
+	b := map[string]int{"get": 2, "delete": 5}
+	sum := func(_ string, av, bv int) int { return av + bv }
+
+	got := MergeWith(a, b, sum)
+	want := map[string]int{"get": 5, "post": 1, "delete": 5}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("MergeWith() = %v, want %v", got, want)
+	}
+
+	// 入参不应被修改
+	if !reflect.DeepEqual(a, map[string]int{"get": 3, "post": 1}) {
+		t.Errorf("input map modified: %v", a)
+	}
+
+	// 没有重叠的 key 时不调用 resolve
+	disjoint := MergeWith(map[string]int{"a": 1}, map[string]int{"b": 2}, func(string, int, int) int {
+		t.Error("resolve should not be called for disjoint keys")
+		return 0
+	})
+	if !reflect.DeepEqual(disjoint, map[string]int{"a": 1, "b": 2}) {
+		t.Errorf("MergeWith() = %v, want map[a:1 b:2]", disjoint)
+	}
+}
+
+func TestFilterMap(t *testing.T) {
+	input := map[string]int{"a": 1, "b": 5, "c": 10}
+
+	got := FilterMap(input, func(_ string, v int) bool {
+		return v >= 5
+	})
+	want := map[string]int{"b": 5, "c": 10}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FilterMap() = %v, want %v", got, want)
+	}
+	if len(input) != 3 {
+		t.Errorf("input map modified: %v", input)
+	}
+}
+
+func TestMapValues(t *testing.T) {
+	input := map[string]int{"a": 1, "b": 2}
+
+	got := MapValues(input, func(v int) int {
+		return v * 2
+	})
+	want := map[string]int{"a": 2, "b": 4}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("MapValues() = %v, want %v", got, want)
+	}
+	if !reflect.DeepEqual(input, map[string]int{"a": 1, "b": 2}) {
+		t.Errorf("input map modified: %v", input)
+	}
+}
+
+func TestGroupAndAggregate(t *testing.T) {
+	type transaction struct {
+		User   string
+		Amount int
+	}
+	txs := []transaction{
+		{User: "alice", Amount: 10},
+		{User: "bob", Amount: 5},
+		{User: "alice", Amount: 20},
+		{User: "carol", Amount: 7},
+		{User: "bob", Amount: 1},
+	}
+	byUser := func(tx transaction) string { return tx.User }
+
+	sums := GroupAndAggregate(txs, byUser, func(items []transaction) int {
+		return Sum(MapColumn(items, func(tx transaction) int { return tx.Amount }))
+	})
+	if want := map[string]int{"alice": 30, "bob": 6, "carol": 7}; !reflect.DeepEqual(sums, want) {
+		t.Errorf("GroupAndAggregate() sums = %v, want %v", sums, want)
+	}
+
+	counts := GroupAndAggregate(txs, byUser, func(items []transaction) int {
+		return len(items)
+	})
+	if want := map[string]int{"alice": 2, "bob": 2, "carol": 1}; !reflect.DeepEqual(counts, want) {
+		t.Errorf("GroupAndAggregate() counts = %v, want %v", counts, want)
+	}
+
+	empty := GroupAndAggregate(nil, byUser, func(items []transaction) int { return len(items) })
+	if len(empty) != 0 {
+		t.Errorf("GroupAndAggregate(nil) = %v, want empty map", empty)
+	}
+}
@@ -0,0 +1,124 @@
+package utils
+
+import (
+	"container/list"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// MemoizeWithOptions 返回带缓存的 fn，同时支持过期时间与 LRU 容量限制
+//
+//	ttl        缓存过期时间，过期后再次调用会重新执行 fn
+//	maxEntries 最多缓存的参数个数，超过后淘汰最久未使用的参数，<=0 表示不限制
+//
+// 以参数 K 本身作为缓存的 key，K 为接口类型时 int(1) 与 int64(1) 是不同的参数；
+// 同一参数的并发调用只会执行一次 fn；fn 返回错误时不缓存，panic 时返回 ErrLoadPanic。
+func MemoizeWithOptions[K comparable, V any](fn func(K) (V, error), ttl time.Duration, maxEntries int) func(K) (V, error) {
+	c := &memoCache[K, V]{
+		fn:      fn,
+		ttl:     ttl,
+		entries: make(map[K]*memoEntry[V]),
+	}
+	if maxEntries > 0 {
+		c.maxEntries = maxEntries
+		c.lru = list.New()
+	}
+	return c.call
+}
+
+// memoEntry 一个参数的缓存结果，done 关闭前结果正在加载
+type memoEntry[V any] struct {
+	done     chan struct{}
+	val      V
+	err      error
+	loadedAt time.Time
+	elem     *list.Element // 在 LRU 链表中的位置，未限制容量时为 nil
+}
+
+// memoCache MemoizeWithOptions 的缓存，以 K 为 key，加载中的结果兼作 singleflight
+type memoCache[K comparable, V any] struct {
+	fn  func(K) (V, error)
+	ttl time.Duration
+
+	mu         sync.Mutex
+	entries    map[K]*memoEntry[V]
+	maxEntries int
+	lru        *list.List // 按访问时间排序的参数，队头为最近访问
+}
+
+func (c *memoCache[K, V]) call(k K) (V, error) {
+	c.mu.Lock()
+	if e, ok := c.entries[k]; ok {
+		select {
+		case <-e.done:
+			if e.err == nil && time.Since(e.loadedAt) < c.ttl {
+				if e.elem != nil {
+					c.lru.MoveToFront(e.elem)
+				}
+				c.mu.Unlock()
+				return e.val, nil
+			}
+			// 已过期或出错，重新加载
+			c.deleteLocked(k)
+		default:
+			// 正在加载，等待同一次加载的结果
+			c.mu.Unlock()
+			<-e.done
+			return e.val, e.err
+		}
+	}
+
+	e := &memoEntry[V]{done: make(chan struct{})}
+	c.entries[k] = e
+	if c.lru != nil {
+		e.elem = c.lru.PushFront(k)
+		for c.lru.Len() > c.maxEntries {
+			c.deleteLocked(c.lru.Back().Value.(K))
+		}
+	}
+	c.mu.Unlock()
+
+	e.val, e.err = c.load(k)
+	e.loadedAt = time.Now()
+	close(e.done)
+
+	if e.err != nil {
+		c.mu.Lock()
+		if c.entries[k] == e {
+			c.deleteLocked(k)
+		}
+		c.mu.Unlock()
+	}
+	return e.val, e.err
+}
+
+// load 执行 fn，panic 时转换为 ErrLoadPanic，避免 panic 传播给任意一个等待者
+func (c *memoCache[K, V]) load(k K) (v V, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			var zero V
+			v = zero
+			err = fmt.Errorf("%w: key=%#v, %v", ErrLoadPanic, k, r)
+		}
+	}()
+
+	v, err = c.fn(k)
+	if err != nil {
+		var zero V
+		return zero, err
+	}
+	return v, nil
+}
+
+// deleteLocked 删除缓存项并维护 LRU 链表，调用方需持有 c.mu
+func (c *memoCache[K, V]) deleteLocked(k K) {
+	e, ok := c.entries[k]
+	if !ok {
+		return
+	}
+	if e.elem != nil {
+		c.lru.Remove(e.elem)
+	}
+	delete(c.entries, k)
+}
@@ -0,0 +1,132 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMemoizeWithOptions(t *testing.T) {
+	t.Run("过期后重新执行", func(t *testing.T) {
+		var calls atomic.Int32
+		square := MemoizeWithOptions(func(n int) (int, error) {
+			calls.Add(1)
+			return n * n, nil
+		}, 20*time.Millisecond, 0)
+
+		for i := 0; i < 3; i++ {
+			if got, err := square(3); err != nil || got != 9 {
+				t.Fatalf("square(3) = (%v, %v)", got, err)
+			}
+		}
+		if calls.Load() != 1 {
+			t.Fatalf("过期前函数调用次数应为 1，实际为 %d", calls.Load())
+		}
+
+		time.Sleep(30 * time.Millisecond)
+		_, _ = square(3)
+		if calls.Load() != 2 {
+			t.Fatalf("过期后函数调用次数应为 2，实际为 %d", calls.Load())
+		}
+	})
+
+	t.Run("超过容量淘汰最久未使用的参数", func(t *testing.T) {
+		calls := make(map[string]int)
+		var mu sync.Mutex
+		echo := MemoizeWithOptions(func(s string) (string, error) {
+			mu.Lock()
+			calls[s]++
+			mu.Unlock()
+			return s, nil
+		}, time.Hour, 2)
+
+		_, _ = echo("a")
+		_, _ = echo("b")
+		_, _ = echo("a") // a 成为最近使用
+		_, _ = echo("c") // 淘汰 b
+
+		_, _ = echo("a")
+		if calls["a"] != 1 {
+			t.Errorf("a 不应被淘汰，调用次数应为 1，实际为 %d", calls["a"])
+		}
+		_, _ = echo("b")
+		if calls["b"] != 2 {
+			t.Errorf("b 应已被淘汰，调用次数应为 2，实际为 %d", calls["b"])
+		}
+	})
+
+	t.Run("出错时不缓存", func(t *testing.T) {
+		var calls atomic.Int32
+		errFn := errors.New("load error")
+		f := MemoizeWithOptions(func(n int) (int, error) {
+			calls.Add(1)
+			return 0, errFn
+		}, time.Hour, 10)
+
+		for i := 0; i < 2; i++ {
+			if _, err := f(1); !errors.Is(err, errFn) {
+				t.Fatalf("错误应为 %v，实际为 %v", errFn, err)
+			}
+		}
+		if calls.Load() != 2 {
+			t.Errorf("出错结果不应缓存，调用次数应为 2，实际为 %d", calls.Load())
+		}
+	})
+
+	t.Run("并发调用去重", func(t *testing.T) {
+		var calls atomic.Int32
+		slow := MemoizeWithOptions(func(n int) (int, error) {
+			calls.Add(1)
+			time.Sleep(20 * time.Millisecond)
+			return n + 1, nil
+		}, time.Hour, 10)
+
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if got, err := slow(1); err != nil || got != 2 {
+					t.Errorf("slow(1) = (%v, %v)", got, err)
+				}
+			}()
+		}
+		wg.Wait()
+
+		if calls.Load() != 1 {
+			t.Errorf("函数调用次数应为 1，实际为 %d", calls.Load())
+		}
+	})
+	t.Run("接口类型参数按动态类型区分", func(t *testing.T) {
+		var calls atomic.Int32
+		typeName := MemoizeWithOptions(func(k any) (string, error) {
+			calls.Add(1)
+			return fmt.Sprintf("%T", k), nil
+		}, time.Hour, 10)
+
+		for i := 0; i < 2; i++ {
+			if got, _ := typeName(int(1)); got != "int" {
+				t.Errorf("typeName(int(1)) = %q, want int", got)
+			}
+			if got, _ := typeName(int64(1)); got != "int64" {
+				t.Errorf("typeName(int64(1)) = %q, want int64", got)
+			}
+		}
+		if calls.Load() != 2 {
+			t.Errorf("函数调用次数应为 2，实际为 %d", calls.Load())
+		}
+	})
+
+	t.Run("panic 转换为错误", func(t *testing.T) {
+		f := MemoizeWithOptions(func(n int) (int, error) {
+			panic("boom")
+		}, time.Hour, 0)
+
+		if _, err := f(1); !errors.Is(err, ErrLoadPanic) {
+			t.Errorf("错误应为 %v，实际为 %v", ErrLoadPanic, err)
+		}
+	})
+}
@@ -0,0 +1,205 @@
+package utils
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"golang.org/x/sync/singleflight"
+)
+
+// releaseLockScript 只有锁仍由当前持有者持有时才删除，避免误删其他副本在锁过期后重新获取的锁
+var releaseLockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+
+// RedisCacheOption NewRedisCache 的参数
+type RedisCacheOption struct {
+	// Expire 缓存过期时间，<= 0 表示不过期
+	Expire time.Duration
+
+	// Prefix 缓存 key 的前缀，用于隔离不同业务，如 "user:"
+	Prefix string
+
+	// LockTTL GetOrSet 加载数据时分布式锁的过期时间，应大于加载函数的最长耗时，默认 10 秒
+	LockTTL time.Duration
+
+	// LockWait 其他副本持有锁时等待其写入缓存的最长时间，超时后在本地直接加载，默认 3 秒
+	LockWait time.Duration
+
+	// Unmarshal 将缓存内容反序列化为 Get 的返回值
+	// 缓存内容为 json.Marshal 的结果，默认反序列化为 interface{}，即对象为 map[string]interface{}、数字为 float64；
+	// 需要具体类型时可自定义，如反序列化为 *User
+	Unmarshal func(data []byte) (interface{}, error)
+
+	// OnError 访问 Redis 或序列化出错时的回调，可用于统计或告警
+	// 出错时 Get 视为未命中，Set、Delete 忽略错误
+	OnError func(error)
+}
+
+// RedisCache 基于 Redis 的缓存，多个副本之间共享缓存内容
+//
+// client 由调用方创建并传入（*redis.Client、*redis.ClusterClient 等），RedisCache 不负责关闭
+// GetOrSet 在本进程内使用 singleflight 合并并发加载，副本之间使用 SET NX 分布式锁，
+// 获取锁失败（如 Redis 不可用）时退化为仅在本进程内合并
+type RedisCache struct {
+	client redis.Cmdable
+	opt    RedisCacheOption
+	group  singleflight.Group
+}
+
+// NewRedisCache 创建 RedisCache，opt 为 nil 时使用默认配置
+func NewRedisCache(client redis.Cmdable, opt *RedisCacheOption) *RedisCache {
+	rc := &RedisCache{client: client}
+	if opt != nil {
+		rc.opt = *opt
+	}
+	if rc.opt.LockTTL <= 0 {
+		rc.opt.LockTTL = 10 * time.Second
+	}
+	if rc.opt.LockWait <= 0 {
+		rc.opt.LockWait = 3 * time.Second
+	}
+	if rc.opt.Unmarshal == nil {
+		rc.opt.Unmarshal = func(data []byte) (interface{}, error) {
+			var v interface{}
+			err := json.Unmarshal(data, &v)
+			return v, err
+		}
+	}
+	return rc
+}
+
+// Get 获取缓存，不存在、已过期或访问 Redis 出错时返回 false
+func (rc *RedisCache) Get(key string) (interface{}, bool) {
+	raw, err := rc.client.Get(context.Background(), rc.opt.Prefix+key).Bytes()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			rc.notify(err)
+		}
+		return nil, false
+	}
+	data, err := rc.opt.Unmarshal(raw)
+	if err != nil {
+		rc.notify(fmt.Errorf("unmarshal cache %q: %w", key, err))
+		return nil, false
+	}
+	return data, true
+}
+
+// Set 将 data 序列化为 JSON 写入缓存
+func (rc *RedisCache) Set(key string, data interface{}) {
+	_ = rc.set(key, data)
+}
+
+func (rc *RedisCache) set(key string, data interface{}) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		err = fmt.Errorf("marshal cache %q: %w", key, err)
+		rc.notify(err)
+		return err
+	}
+	if err = rc.client.Set(context.Background(), rc.opt.Prefix+key, raw, max(rc.opt.Expire, 0)).Err(); err != nil {
+		rc.notify(err)
+	}
+	return err
+}
+
+// Delete 删除缓存
+func (rc *RedisCache) Delete(key string) {
+	if err := rc.client.Del(context.Background(), rc.opt.Prefix+key).Err(); err != nil {
+		rc.notify(err)
+	}
+}
+
+// GetOrSet 从缓存获取数据，如果不存在则执行函数获取并设置缓存
+// 由本次调用加载时返回 fn 的原始结果，而不是 JSON 反序列化后的值
+func (rc *RedisCache) GetOrSet(key string, fn func() (interface{}, error)) (interface{}, bool, error) {
+	if data, exists := rc.Get(key); exists {
+		return data, true, nil
+	}
+
+	result, err, _ := rc.group.Do(key, func() (interface{}, error) {
+		return rc.loadWithLock(key, fn)
+	})
+	return result, false, err
+}
+
+// loadWithLock 获取分布式锁后加载数据；锁被其他副本持有时等待其写入缓存，超时后在本地加载
+func (rc *RedisCache) loadWithLock(key string, fn func() (interface{}, error)) (interface{}, error) {
+	ctx := context.Background()
+	lockKey := rc.opt.Prefix + key + ":lock"
+	token := newLockToken()
+
+	locked, err := rc.client.SetNX(ctx, lockKey, token, rc.opt.LockTTL).Result()
+	if err != nil {
+		rc.notify(err)
+		return rc.loadAndSet(key, fn)
+	}
+	if locked {
+		defer func() {
+			if err := releaseLockScript.Run(ctx, rc.client, []string{lockKey}, token).Err(); err != nil {
+				rc.notify(err)
+			}
+		}()
+		// 获取锁之前其他副本可能刚写入缓存
+		if data, exists := rc.Get(key); exists {
+			return data, nil
+		}
+		return rc.loadAndSet(key, fn)
+	}
+
+	ticker := time.NewTicker(20 * time.Millisecond)
+	defer ticker.Stop()
+	deadline := time.After(rc.opt.LockWait)
+	for {
+		select {
+		case <-ticker.C:
+			if data, exists := rc.Get(key); exists {
+				return data, nil
+			}
+		case <-deadline:
+			return rc.loadAndSet(key, fn)
+		}
+	}
+}
+
+// loadAndSet 执行函数获取数据并设置缓存
+// fn panic 时会被 recover 并转换为 ErrLoadPanic，且不会写入缓存
+func (rc *RedisCache) loadAndSet(key string, fn func() (interface{}, error)) (data interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			data = nil
+			err = fmt.Errorf("%w: key=%q, %v", ErrLoadPanic, key, r)
+		}
+	}()
+
+	data, err = fn()
+	if err != nil {
+		return nil, err
+	}
+
+	// 写入失败不影响本次返回，下次访问时重新加载
+	_ = rc.set(key, data)
+	return data, nil
+}
+
+func (rc *RedisCache) notify(err error) {
+	if rc.opt.OnError != nil {
+		rc.opt.OnError(err)
+	}
+}
+
+// newLockToken 生成分布式锁的持有者标识
+func newLockToken() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
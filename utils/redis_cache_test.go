@@ -0,0 +1,127 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func newTestRedis(t *testing.T) (*miniredis.Miniredis, *redis.Client) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	return mr, client
+}
+
+func TestRedisCache_GetSetDelete(t *testing.T) {
+	mr, client := newTestRedis(t)
+	rc := NewRedisCache(client, &RedisCacheOption{Prefix: "test:", Expire: time.Minute})
+
+	if _, ok := rc.Get("user"); ok {
+		t.Fatal("Get() on empty cache should miss")
+	}
+
+	rc.Set("user", map[string]interface{}{"name": "alice", "age": 18})
+	got, ok := rc.Get("user")
+	if !ok {
+		t.Fatal("Get() after Set should hit")
+	}
+	want := map[string]interface{}{"name": "alice", "age": float64(18)}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Get() = %#v, want %#v", got, want)
+	}
+	if !mr.Exists("test:user") {
+		t.Error("key should be stored with prefix")
+	}
+
+	// 过期
+	mr.FastForward(2 * time.Minute)
+	if _, ok := rc.Get("user"); ok {
+		t.Error("Get() after expire should miss")
+	}
+
+	rc.Set("user", "bob")
+	rc.Delete("user")
+	if _, ok := rc.Get("user"); ok {
+		t.Error("Get() after Delete should miss")
+	}
+}
+
+func TestRedisCache_Unmarshal(t *testing.T) {
+	type user struct {
+		Name string
+	}
+	_, client := newTestRedis(t)
+	rc := NewRedisCache(client, &RedisCacheOption{
+		Unmarshal: func(data []byte) (interface{}, error) {
+			var u user
+			err := json.Unmarshal(data, &u)
+			return &u, err
+		},
+	})
+
+	rc.Set("u", user{Name: "alice"})
+	got, ok := rc.Get("u")
+	if u, _ := got.(*user); !ok || u == nil || u.Name != "alice" {
+		t.Errorf("Get() = %#v, %v, want &user{alice}", got, ok)
+	}
+}
+
+func TestRedisCache_GetOrSet(t *testing.T) {
+	_, client := newTestRedis(t)
+	// 两个 RedisCache 共享同一个 Redis，模拟两个副本
+	replicas := []*RedisCache{NewRedisCache(client, nil), NewRedisCache(client, nil)}
+
+	var calls atomic.Int32
+	load := func() (interface{}, error) {
+		calls.Add(1)
+		time.Sleep(50 * time.Millisecond)
+		return "value", nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(rc *RedisCache) {
+			defer wg.Done()
+			data, _, err := rc.GetOrSet("key", load)
+			if err != nil || data != "value" {
+				t.Errorf("GetOrSet() = %v, %v, want value, nil", data, err)
+			}
+		}(replicas[i%2])
+	}
+	wg.Wait()
+
+	if got := calls.Load(); got != 1 {
+		t.Errorf("load called %d times across replicas, want 1", got)
+	}
+
+	data, fromCache, err := replicas[0].GetOrSet("key", load)
+	if err != nil || data != "value" || !fromCache {
+		t.Errorf("GetOrSet() = %v, %v, %v, want value from cache", data, fromCache, err)
+	}
+}
+
+func TestRedisCache_GetOrSetError(t *testing.T) {
+	_, client := newTestRedis(t)
+	rc := NewRedisCache(client, nil)
+
+	errLoad := errors.New("load error")
+	if _, _, err := rc.GetOrSet("key", func() (interface{}, error) { return nil, errLoad }); !errors.Is(err, errLoad) {
+		t.Errorf("GetOrSet() error = %v, want %v", err, errLoad)
+	}
+	if _, _, err := rc.GetOrSet("key", func() (interface{}, error) { panic("boom") }); !errors.Is(err, ErrLoadPanic) {
+		t.Errorf("GetOrSet() error = %v, want ErrLoadPanic", err)
+	}
+	if _, ok := rc.Get("key"); ok {
+		t.Error("failed load should not be cached")
+	}
+}
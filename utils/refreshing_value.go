@@ -0,0 +1,101 @@
+package utils
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// RefreshingValue 在后台定时刷新的值，适用于功能开关、动态配置等读多写少的场景
+// Get 始终返回最近一次加载成功的值，加载失败时保留旧值，不会因配置中心抖动读到零值
+type RefreshingValue[T any] struct {
+	load  func() (T, error)
+	value atomic.Pointer[T]
+	err   atomic.Pointer[error]
+
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewRefreshingValue 创建后台刷新的值，创建时同步加载一次，之后每隔 interval 在后台刷新
+// interval <= 0 时只加载一次，不启动后台刷新；不再使用时需调用 Stop 结束后台刷新
+func NewRefreshingValue[T any](interval time.Duration, load func() (T, error)) *RefreshingValue[T] {
+	rv := &RefreshingValue[T]{
+		load: load,
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+	rv.refresh()
+
+	if interval <= 0 {
+		close(rv.done)
+		return rv
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer func() {
+			ticker.Stop()
+			close(rv.done)
+		}()
+
+		for {
+			select {
+			case <-ticker.C:
+				rv.refresh()
+			case <-rv.stop:
+				return
+			}
+		}
+	}()
+	return rv
+}
+
+// Get 返回最近一次加载成功的值，从未加载成功时返回零值
+func (rv *RefreshingValue[T]) Get() T {
+	if v := rv.value.Load(); v != nil {
+		return *v
+	}
+	var zero T
+	return zero
+}
+
+// Err 返回最近一次加载的错误，最近一次加载成功时返回 nil
+func (rv *RefreshingValue[T]) Err() error {
+	if err := rv.err.Load(); err != nil {
+		return *err
+	}
+	return nil
+}
+
+// Stop 结束后台刷新，等待进行中的加载完成后返回，可重复调用
+func (rv *RefreshingValue[T]) Stop() {
+	rv.stopOnce.Do(func() {
+		close(rv.stop)
+	})
+	<-rv.done
+}
+
+// refresh 加载一次新值，失败时保留旧值
+// load panic 时会被 recover 并转换为 ErrLoadPanic，避免后台协程崩溃
+func (rv *RefreshingValue[T]) refresh() {
+	var err error
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%w: %v", ErrLoadPanic, r)
+		}
+		if err != nil {
+			rv.err.Store(&err)
+		} else {
+			rv.err.Store(nil)
+		}
+	}()
+
+	v, err := rv.load()
+	if err != nil {
+		return
+	}
+	rv.value.Store(&v)
+}
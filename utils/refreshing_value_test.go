@@ -0,0 +1,94 @@
+package utils
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// waitFor 在 timeout 内轮询 cond，超时返回 false
+func waitFor(timeout time.Duration, cond func() bool) bool {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if cond() {
+			return true
+		}
+		time.Sleep(time.Millisecond)
+	}
+	return cond()
+}
+
+func TestRefreshingValue(t *testing.T) {
+	var version atomic.Int64
+	var fail atomic.Bool
+	errLoad := errors.New("config center unavailable")
+
+	rv := NewRefreshingValue(5*time.Millisecond, func() (int64, error) {
+		if fail.Load() {
+			return 0, errLoad
+		}
+		return version.Add(1), nil
+	})
+	defer rv.Stop()
+
+	// 创建时同步加载
+	if got := rv.Get(); got != 1 {
+		t.Fatalf("Get() after create = %d, want 1", got)
+	}
+
+	// 后台刷新后返回新值
+	if !waitFor(time.Second, func() bool { return rv.Get() >= 3 }) {
+		t.Fatalf("Get() = %d, want refreshed value >= 3", rv.Get())
+	}
+
+	// 刷新失败时保留旧值
+	fail.Store(true)
+	if !waitFor(time.Second, func() bool { return rv.Err() != nil }) {
+		t.Fatal("Err() should report the failed refresh")
+	}
+	stale := rv.Get()
+	time.Sleep(20 * time.Millisecond)
+	if got := rv.Get(); got != stale || got == 0 {
+		t.Errorf("Get() after failed refresh = %d, want stale value %d", got, stale)
+	}
+	if !errors.Is(rv.Err(), errLoad) {
+		t.Errorf("Err() = %v, want %v", rv.Err(), errLoad)
+	}
+
+	// 恢复后继续刷新
+	fail.Store(false)
+	if !waitFor(time.Second, func() bool { return rv.Get() > stale && rv.Err() == nil }) {
+		t.Errorf("Get() = %d, Err() = %v, want refreshed after recovery", rv.Get(), rv.Err())
+	}
+}
+
+func TestRefreshingValue_Stop(t *testing.T) {
+	var loads atomic.Int32
+	rv := NewRefreshingValue(time.Millisecond, func() (string, error) {
+		loads.Add(1)
+		return "v", nil
+	})
+	rv.Stop()
+	rv.Stop() // 重复调用不会 panic
+
+	n := loads.Load()
+	time.Sleep(10 * time.Millisecond)
+	if got := loads.Load(); got != n {
+		t.Errorf("loads after Stop = %d, want %d", got, n)
+	}
+}
+
+func TestRefreshingValue_InitialFailure(t *testing.T) {
+	rv := NewRefreshingValue(0, func() (*int, error) {
+		panic("bad config")
+	})
+	defer rv.Stop()
+
+	if got := rv.Get(); got != nil {
+		t.Errorf("Get() = %v, want zero value", got)
+	}
+	if !errors.Is(rv.Err(), ErrLoadPanic) {
+		t.Errorf("Err() = %v, want ErrLoadPanic", rv.Err())
+	}
+}
@@ -0,0 +1,69 @@
+package utils
+
+import (
+	"context"
+	"sync"
+)
+
+// RequestCache 生命周期与 context 绑定的缓存，适用于请求级别的缓存
+// ctx 结束（取消或超时）后会清空所有缓存项，之后的 Set 不再生效
+type RequestCache struct {
+	items map[string]interface{}
+	mutex sync.RWMutex
+	done  bool
+}
+
+// NewRequestCache 创建与 ctx 绑定的缓存，ctx 结束时自动清空
+func NewRequestCache(ctx context.Context) *RequestCache {
+	rc := &RequestCache{
+		items: make(map[string]interface{}),
+	}
+	// ctx 永不结束时 AfterFunc 不会启动 goroutine，不会泄漏
+	context.AfterFunc(ctx, rc.close)
+	return rc
+}
+
+// Get 从缓存获取数据
+func (rc *RequestCache) Get(key string) (interface{}, bool) {
+	rc.mutex.RLock()
+	defer rc.mutex.RUnlock()
+
+	data, exists := rc.items[key]
+	return data, exists
+}
+
+// Set 设置缓存数据，ctx 结束后调用不生效
+func (rc *RequestCache) Set(key string, data interface{}) {
+	rc.mutex.Lock()
+	defer rc.mutex.Unlock()
+
+	if rc.done {
+		return
+	}
+	rc.items[key] = data
+}
+
+// Delete 删除缓存数据
+func (rc *RequestCache) Delete(key string) {
+	rc.mutex.Lock()
+	defer rc.mutex.Unlock()
+
+	delete(rc.items, key)
+}
+
+// Len 返回缓存条数
+func (rc *RequestCache) Len() int {
+	rc.mutex.RLock()
+	defer rc.mutex.RUnlock()
+
+	return len(rc.items)
+}
+
+// close ctx 结束时清空缓存
+func (rc *RequestCache) close() {
+	rc.mutex.Lock()
+	defer rc.mutex.Unlock()
+
+	rc.done = true
+	clear(rc.items)
+}
@@ -0,0 +1,39 @@
+package utils
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRequestCache(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	rc := NewRequestCache(ctx)
+
+	rc.Set("k1", "v1")
+	rc.Set("k2", "v2")
+	if v, ok := rc.Get("k1"); !ok || v != "v1" {
+		t.Fatalf("Get(k1) = (%v, %v), want (v1, true)", v, ok)
+	}
+	rc.Delete("k2")
+	if rc.Len() != 1 {
+		t.Fatalf("Len() = %d, want 1", rc.Len())
+	}
+
+	cancel()
+	deadline := time.Now().Add(time.Second)
+	for rc.Len() != 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("ctx 取消后缓存应被清空")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	if _, ok := rc.Get("k1"); ok {
+		t.Error("ctx 取消后 k1 应不存在")
+	}
+	rc.Set("k3", "v3")
+	if _, ok := rc.Get("k3"); ok {
+		t.Error("ctx 取消后 Set 不应生效")
+	}
+}
@@ -0,0 +1,12 @@
+package utils
+
+import "github.com/Twelveeee/golib/ring"
+
+// RingBuffer 固定容量的环形缓冲区（FIFO），写满后新元素覆盖最旧的元素，并发安全
+// 实现位于不依赖其他包的 ring 包，logger/handler 也使用同一实现
+type RingBuffer[T any] = ring.Buffer[T]
+
+// NewRingBuffer 创建容量为 capacity 的环形缓冲区，capacity <= 0 时容量为 1
+func NewRingBuffer[T any](capacity int) *RingBuffer[T] {
+	return ring.New[T](capacity)
+}
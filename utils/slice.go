@@ -1,5 +1,16 @@
 package utils
 
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"math/rand"
+	"slices"
+)
+
+// ErrTypeMismatch FromAnySlice 中元素类型与目标类型不一致时返回的错误
+var ErrTypeMismatch = errors.New("type mismatch")
+
 func ForEach[T any](data []T, f func(T) error) error {
 	for _, item := range data {
 		if err := f(item); err != nil {
@@ -9,6 +20,25 @@ func ForEach[T any](data []T, f func(T) error) error {
 	return nil
 }
 
+// ForEachIndexed 与 ForEach 相同，但 f 的第一个参数为元素下标
+func ForEachIndexed[T any](data []T, f func(int, T) error) error {
+	for idx, item := range data {
+		if err := f(idx, item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ForEachUntil 遍历切片，f 返回 true 时停止遍历（类似 break），不视为错误
+func ForEachUntil[T any](data []T, f func(T) bool) {
+	for _, item := range data {
+		if f(item) {
+			return
+		}
+	}
+}
+
 func FindIndex[T any](data []T, f func(T) bool) int {
 	for idx, item := range data {
 		if f(item) {
@@ -18,6 +48,34 @@ func FindIndex[T any](data []T, f func(T) bool) int {
 	return -1
 }
 
+// Find 查找第一个满足条件的元素，找不到返回零值和 false
+func Find[T any](data []T, f func(T) bool) (T, bool) {
+	if idx := FindIndex(data, f); idx >= 0 {
+		return data[idx], true
+	}
+	var zero T
+	return zero, false
+}
+
+// FindLastIndex 从后往前查找第一个满足条件的元素下标，找不到返回 -1
+func FindLastIndex[T any](data []T, f func(T) bool) int {
+	for idx := len(data) - 1; idx >= 0; idx-- {
+		if f(data[idx]) {
+			return idx
+		}
+	}
+	return -1
+}
+
+// FindLast 从后往前查找第一个满足条件的元素，找不到返回零值和 false
+func FindLast[T any](data []T, f func(T) bool) (T, bool) {
+	if idx := FindLastIndex(data, f); idx >= 0 {
+		return data[idx], true
+	}
+	var zero T
+	return zero, false
+}
+
 func FindItem[T comparable](data []T, target T) int {
 	for idx, item := range data {
 		if target == item {
@@ -27,6 +85,28 @@ func FindItem[T comparable](data []T, target T) int {
 	return -1
 }
 
+// ToAnySlice 将带类型的切片转换为 []interface{}，便于传给 fmt、SQL 等可变参数
+func ToAnySlice[T any](data []T) []interface{} {
+	result := make([]interface{}, len(data))
+	for i, item := range data {
+		result[i] = item
+	}
+	return result
+}
+
+// FromAnySlice 将 []interface{} 逐个断言为 T，存在类型不一致的元素时返回 ErrTypeMismatch
+func FromAnySlice[T any](data []interface{}) ([]T, error) {
+	result := make([]T, len(data))
+	for i, item := range data {
+		v, ok := item.(T)
+		if !ok {
+			return nil, fmt.Errorf("%w: element %d is %T, want %T", ErrTypeMismatch, i, item, v)
+		}
+		result[i] = v
+	}
+	return result, nil
+}
+
 func Map[T any, K any](data []T, f func(T) K) []K {
 	result := make([]K, 0, len(data))
 	for _, item := range data {
@@ -35,6 +115,15 @@ func Map[T any, K any](data []T, f func(T) K) []K {
 	return result
 }
 
+// MapIndexed 与 Map 相同，但 f 的第一个参数为元素下标
+func MapIndexed[T any, K any](data []T, f func(int, T) K) []K {
+	result := make([]K, 0, len(data))
+	for idx, item := range data {
+		result = append(result, f(idx, item))
+	}
+	return result
+}
+
 func Unique[T comparable](data []T) []T {
 	m := make(map[T]struct{})
 	for _, item := range data {
@@ -56,6 +145,43 @@ func InArray[T comparable](target T, data []T) bool {
 	return false
 }
 
+// ContainsAll 判断 targets 中的元素是否都在 data 中，targets 为空时返回 true，如判断是否拥有全部所需权限
+func ContainsAll[T comparable](data, targets []T) bool {
+	if len(targets) == 0 {
+		return true
+	}
+	set := toSet(data)
+	for _, target := range targets {
+		if _, ok := set[target]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// ContainsAny 判断 targets 中是否有任一元素在 data 中，targets 为空时返回 false，如判断是否带有任一标签
+func ContainsAny[T comparable](data, targets []T) bool {
+	if len(targets) == 0 || len(data) == 0 {
+		return false
+	}
+	set := toSet(data)
+	for _, target := range targets {
+		if _, ok := set[target]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// toSet 将切片转换为集合
+func toSet[T comparable](data []T) map[T]struct{} {
+	set := make(map[T]struct{}, len(data))
+	for _, item := range data {
+		set[item] = struct{}{}
+	}
+	return set
+}
+
 func Filter[T any](data []T, f func(T) bool) []T {
 	result := make([]T, 0, len(data))
 	for _, item := range data {
@@ -66,17 +192,70 @@ func Filter[T any](data []T, f func(T) bool) []T {
 	return result
 }
 
+// Chunk 按 size 将 data 切分为多个分块，最后一个分块可能不满 size
+// 分块直接引用 data 的底层数组，但容量被限制为分块长度，对分块 append 不会覆盖下一个分块
+// data 为空时返回空切片，size <= 0 时将 data 整体作为一个分块
 func Chunk[T any](data []T, size int) [][]T {
-	if len(data) <= size {
-		return [][]T{data}
+	if len(data) == 0 {
+		return [][]T{}
+	}
+	if size <= 0 {
+		size = len(data)
 	}
-	result := make([][]T, 0, len(data)/8)
+	result := make([][]T, 0, (len(data)+size-1)/size)
 	for i := 0; i < len(data); i += size {
-		if i+size <= len(data)-1 {
-			result = append(result, data[i:i+size])
-		} else {
-			result = append(result, data[i:])
-		}
+		end := min(i+size, len(data))
+		result = append(result, data[i:end:end])
+	}
+	return result
+}
+
+// Flatten 将二维切片按顺序拼接为一维切片，是 Chunk 的逆操作，返回新切片
+func Flatten[T any](data [][]T) []T {
+	n := 0
+	for _, item := range data {
+		n += len(item)
+	}
+	result := make([]T, 0, n)
+	for _, item := range data {
+		result = append(result, item...)
+	}
+	return result
+}
+
+// ChunkInto 与 Chunk 相同，但复用调用方传入的 dst 存放分块结果，适合在热点循环中反复分块
+//
+//	dst 会被重置长度后复用，容量不足时才会重新分配，因此调用方不应继续持有上一次的返回值
+//	返回的每个分块都直接引用 data 的底层数组（不拷贝），修改分块内容会影响 data，
+//	容量同 Chunk 被限制为分块长度，对分块 append 不会覆盖下一个分块
+//	size <= 0 时将 data 整体作为一个分块
+func ChunkInto[T any](data []T, size int, dst [][]T) [][]T {
+	dst = dst[:0]
+	if len(data) == 0 {
+		return dst
+	}
+	if size <= 0 {
+		size = len(data)
+	}
+	for i := 0; i < len(data); i += size {
+		end := min(i+size, len(data))
+		dst = append(dst, data[i:end:end])
+	}
+	return dst
+}
+
+// ChunkMap 按 size 分块，并对每个分块调用 f，按顺序返回每个分块的结果，不会创建中间的 [][]T
+// 传给 f 的分块直接引用 data 的底层数组，f 不应持有或修改分块；size <= 0 时将 data 整体作为一个分块
+func ChunkMap[T any, R any](data []T, size int, f func([]T) R) []R {
+	if len(data) == 0 {
+		return []R{}
+	}
+	if size <= 0 {
+		size = len(data)
+	}
+	result := make([]R, 0, (len(data)+size-1)/size)
+	for i := 0; i < len(data); i += size {
+		result = append(result, f(data[i:min(i+size, len(data))]))
 	}
 	return result
 }
@@ -87,3 +266,243 @@ func Reverse[T any](data []T) {
 		data[i], data[j] = data[j], data[i]
 	}
 }
+
+// Count 统计 data 中等于 target 的元素个数
+func Count[T comparable](data []T, target T) int {
+	n := 0
+	for _, item := range data {
+		if item == target {
+			n++
+		}
+	}
+	return n
+}
+
+// CountBy 统计满足 f 的元素个数，等价于 len(Filter(data, f))，但不会分配新切片
+func CountBy[T any](data []T, f func(T) bool) int {
+	n := 0
+	for _, item := range data {
+		if f(item) {
+			n++
+		}
+	}
+	return n
+}
+
+// Take 返回前 n 个元素，n 超过长度时返回全部，n <= 0 时返回空切片
+// 返回的切片与 data 共享底层数组
+func Take[T any](data []T, n int) []T {
+	return data[:min(max(n, 0), len(data))]
+}
+
+// Drop 跳过前 n 个元素，返回剩余部分，n 超过长度时返回空切片，n <= 0 时返回全部
+// 返回的切片与 data 共享底层数组
+func Drop[T any](data []T, n int) []T {
+	return data[min(max(n, 0), len(data)):]
+}
+
+// TakeWhile 从头开始取元素，直到第一个不满足 f 的元素为止（不含该元素）
+// 与 Filter 不同，遇到第一个不满足条件的元素即停止；返回的切片与 data 共享底层数组
+func TakeWhile[T any](data []T, f func(T) bool) []T {
+	for idx, item := range data {
+		if !f(item) {
+			return data[:idx]
+		}
+	}
+	return data
+}
+
+// DropWhile 从头开始跳过满足 f 的元素，返回从第一个不满足 f 的元素开始的剩余部分
+// 返回的切片与 data 共享底层数组
+func DropWhile[T any](data []T, f func(T) bool) []T {
+	for idx, item := range data {
+		if !f(item) {
+			return data[idx:]
+		}
+	}
+	return data[len(data):]
+}
+
+// SortBy 按 keyFunc 提取的 key 升序原地排序，不保证相等元素的相对顺序
+// keyFunc 在每次比较时调用，开销较大时可先用 Map 提取 key
+func SortBy[T any, K cmp.Ordered](data []T, keyFunc func(T) K) {
+	slices.SortFunc(data, func(a, b T) int {
+		return cmp.Compare(keyFunc(a), keyFunc(b))
+	})
+}
+
+// SortStableBy 与 SortBy 相同，但 key 相等的元素保持原有的相对顺序
+func SortStableBy[T any, K cmp.Ordered](data []T, keyFunc func(T) K) {
+	slices.SortStableFunc(data, func(a, b T) int {
+		return cmp.Compare(keyFunc(a), keyFunc(b))
+	})
+}
+
+// Shuffle 使用 Fisher–Yates 算法原地打乱 data
+// 可传入 rng 使用指定的随机数生成器（如固定种子以便测试复现），否则使用 math/rand 的全局生成器
+func Shuffle[T any](data []T, rng ...*rand.Rand) {
+	intn := randIntn(rng)
+	for i := len(data) - 1; i > 0; i-- {
+		j := intn(i + 1)
+		data[i], data[j] = data[j], data[i]
+	}
+}
+
+// Sample 随机选取 n 个不同位置的元素，返回新切片，不会修改 data
+// n 超过长度时返回打乱后的全部元素，n <= 0 时返回空切片；rng 的含义同 Shuffle
+func Sample[T any](data []T, n int, rng ...*rand.Rand) []T {
+	n = min(max(n, 0), len(data))
+	pool := append([]T(nil), data...)
+	intn := randIntn(rng)
+	// 只需打乱前 n 个位置
+	for i := 0; i < n; i++ {
+		j := i + intn(len(pool)-i)
+		pool[i], pool[j] = pool[j], pool[i]
+	}
+	return pool[:n:n]
+}
+
+// SampleEvery 按固定步长抽取元素（下标 0, n, 2n, ...），返回新切片，不会修改 data
+// 适用于大数据量下的日志打印、预览；n <= 1 时返回全部元素的拷贝
+func SampleEvery[T any](data []T, n int) []T {
+	n = max(n, 1)
+	result := make([]T, 0, (len(data)+n-1)/n)
+	for i := 0; i < len(data); i += n {
+		result = append(result, data[i])
+	}
+	return result
+}
+
+// randIntn 返回 rng 中第一个非 nil 生成器的 Intn，没有时使用全局生成器
+func randIntn(rng []*rand.Rand) func(int) int {
+	if len(rng) > 0 && rng[0] != nil {
+		return rng[0].Intn
+	}
+	return rand.Intn
+}
+
+// Insert 在 index 位置插入 values，返回新切片，不会修改 data
+// index 的有效范围为 [0, len(data)]，超出范围时原样返回 data
+func Insert[T any](data []T, index int, values ...T) []T {
+	if index < 0 || index > len(data) {
+		return data
+	}
+	result := make([]T, 0, len(data)+len(values))
+	result = append(result, data[:index]...)
+	result = append(result, values...)
+	return append(result, data[index:]...)
+}
+
+// RemoveAt 删除 index 位置的元素，返回新切片，不会修改 data
+// index 的有效范围为 [0, len(data))，超出范围时原样返回 data
+func RemoveAt[T any](data []T, index int) []T {
+	if index < 0 || index >= len(data) {
+		return data
+	}
+	result := make([]T, 0, len(data)-1)
+	result = append(result, data[:index]...)
+	return append(result, data[index+1:]...)
+}
+
+// FlattenDeep 将嵌套的 []interface{} 展开 depth 层，depth < 0 时完全展开，depth == 0 时不展开
+// 适用于处理 JSON 解析出的多层嵌套数组，返回新切片，不会修改 data
+func FlattenDeep(data []interface{}, depth int) []interface{} {
+	result := make([]interface{}, 0, len(data))
+	return flattenInto(result, data, depth)
+}
+
+func flattenInto(dst []interface{}, data []interface{}, depth int) []interface{} {
+	for _, item := range data {
+		if nested, ok := item.([]interface{}); ok && depth != 0 {
+			dst = flattenInto(dst, nested, depth-1)
+			continue
+		}
+		dst = append(dst, item)
+	}
+	return dst
+}
+
+// Number 可进行算术运算的数值类型
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// Sum 求和，空切片返回零值
+func Sum[T Number](data []T) T {
+	var sum T
+	for _, item := range data {
+		sum += item
+	}
+	return sum
+}
+
+// Min 返回最小值，空切片时 ok 为 false
+func Min[T cmp.Ordered](data []T) (T, bool) {
+	var result T
+	if len(data) == 0 {
+		return result, false
+	}
+	result = data[0]
+	for _, item := range data[1:] {
+		if item < result {
+			result = item
+		}
+	}
+	return result, true
+}
+
+// Max 返回最大值，空切片时 ok 为 false
+func Max[T cmp.Ordered](data []T) (T, bool) {
+	var result T
+	if len(data) == 0 {
+		return result, false
+	}
+	result = data[0]
+	for _, item := range data[1:] {
+		if item > result {
+			result = item
+		}
+	}
+	return result, true
+}
+
+// Pair 由两个元素组成的元组
+type Pair[A any, B any] struct {
+	First  A
+	Second B
+}
+
+// Zip 将两个切片按位置组合成 Pair 切片，长度以较短的切片为准
+func Zip[A any, B any](a []A, b []B) []Pair[A, B] {
+	n := min(len(a), len(b))
+	result := make([]Pair[A, B], n)
+	for i := 0; i < n; i++ {
+		result[i] = Pair[A, B]{First: a[i], Second: b[i]}
+	}
+	return result
+}
+
+// CartesianProduct 返回 a 与 b 的笛卡尔积，按 a 的顺序优先排列
+// 结果长度为 len(a)*len(b)，任一切片为空时返回空切片
+func CartesianProduct[A any, B any](a []A, b []B) []Pair[A, B] {
+	result := make([]Pair[A, B], 0, len(a)*len(b))
+	for _, x := range a {
+		for _, y := range b {
+			result = append(result, Pair[A, B]{First: x, Second: y})
+		}
+	}
+	return result
+}
+
+// Unzip Zip 的逆操作，将 Pair 切片拆分为两个切片
+func Unzip[A any, B any](pairs []Pair[A, B]) ([]A, []B) {
+	as := make([]A, len(pairs))
+	bs := make([]B, len(pairs))
+	for i, p := range pairs {
+		as[i] = p.First
+		bs[i] = p.Second
+	}
+	return as, bs
+}
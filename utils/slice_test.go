@@ -2,9 +2,12 @@ package utils
 
 import (
 	"errors"
+	"fmt"
+	"math/rand"
 	"reflect"
 	"sort"
 	"strconv"
+	"strings"
 	"testing"
 )
 
@@ -47,6 +50,39 @@ func TestForEach(t *testing.T) {
 	}
 }
 
+func TestForEachUntil(t *testing.T) {
+	tests := []struct {
+		name string
+		data []int
+		stop func(int) bool
+		want []int
+	}{
+		{
+			name: "遇到第一个true停止",
+			data: []int{1, 2, 3, 4, 3},
+			stop: func(i int) bool { return i == 3 },
+			want: []int{1, 2, 3},
+		}, {
+			name: "从不返回true时遍历全部",
+			data: []int{1, 2, 3, 4},
+			stop: func(int) bool { return false },
+			want: []int{1, 2, 3, 4},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var visited []int
+			ForEachUntil(tt.data, func(i int) bool {
+				visited = append(visited, i)
+				return tt.stop(i)
+			})
+			if !reflect.DeepEqual(visited, tt.want) {
+				t.Errorf("ForEachUntil() visited = %v, want %v", visited, tt.want)
+			}
+		})
+	}
+}
+
 func TestFindIndex(t *testing.T) {
 	type args struct {
 		data []int
@@ -86,6 +122,92 @@ func TestFindIndex(t *testing.T) {
 	}
 }
 
+func TestFind(t *testing.T) {
+	type entry struct {
+		Key   string
+		Value int
+	}
+	data := []entry{{"a", 1}, {"b", 2}, {"a", 3}}
+	tests := []struct {
+		name     string
+		data     []entry
+		f        func(entry) bool
+		wantItem entry
+		wantOk   bool
+	}{
+		{
+			name:     "多个匹配返回第一个",
+			data:     data,
+			f:        func(e entry) bool { return e.Key == "a" },
+			wantItem: entry{"a", 1},
+			wantOk:   true,
+		}, {
+			name:   "没有匹配",
+			data:   data,
+			f:      func(e entry) bool { return e.Key == "z" },
+			wantOk: false,
+		}, {
+			name:   "空切片",
+			data:   nil,
+			f:      func(entry) bool { return true },
+			wantOk: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := Find(tt.data, tt.f)
+			if got != tt.wantItem || ok != tt.wantOk {
+				t.Errorf("Find() = (%v, %v), want (%v, %v)", got, ok, tt.wantItem, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestFindLast(t *testing.T) {
+	type entry struct {
+		Key   string
+		Value int
+	}
+	data := []entry{{"a", 1}, {"b", 2}, {"a", 3}, {"c", 4}}
+	tests := []struct {
+		name      string
+		f         func(entry) bool
+		wantIndex int
+		wantItem  entry
+		wantOk    bool
+	}{
+		{
+			name:      "多个匹配返回最后一个",
+			f:         func(e entry) bool { return e.Key == "a" },
+			wantIndex: 2,
+			wantItem:  entry{"a", 3},
+			wantOk:    true,
+		}, {
+			name:      "单个匹配",
+			f:         func(e entry) bool { return e.Key == "b" },
+			wantIndex: 1,
+			wantItem:  entry{"b", 2},
+			wantOk:    true,
+		}, {
+			name:      "没有匹配",
+			f:         func(e entry) bool { return e.Key == "z" },
+			wantIndex: -1,
+			wantOk:    false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FindLastIndex(data, tt.f); got != tt.wantIndex {
+				t.Errorf("FindLastIndex() = %v, want %v", got, tt.wantIndex)
+			}
+			got, ok := FindLast(data, tt.f)
+			if got != tt.wantItem || ok != tt.wantOk {
+				t.Errorf("FindLast() = (%v, %v), want (%v, %v)", got, ok, tt.wantItem, tt.wantOk)
+			}
+		})
+	}
+}
+
 func TestFindItem(t *testing.T) {
 	type args struct {
 		data   []int
@@ -219,6 +341,35 @@ func TestIsIn(t *testing.T) {
 	}
 }
 
+func TestContainsAllAny(t *testing.T) {
+	perms := []string{"read", "write", "delete"}
+	tests := []struct {
+		name    string
+		data    []string
+		targets []string
+		all     bool
+		any     bool
+	}{
+		{"子集", perms, []string{"read", "write"}, true, true},
+		{"超集", perms, []string{"read", "write", "delete", "admin"}, false, true},
+		{"部分相交", perms, []string{"write", "admin"}, false, true},
+		{"不相交", perms, []string{"admin", "owner"}, false, false},
+		{"targets 为空", perms, nil, true, false},
+		{"data 为空", nil, []string{"read"}, false, false},
+		{"targets 含重复元素", perms, []string{"read", "read"}, true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ContainsAll(tt.data, tt.targets); got != tt.all {
+				t.Errorf("ContainsAll(%v, %v) = %v, want %v", tt.data, tt.targets, got, tt.all)
+			}
+			if got := ContainsAny(tt.data, tt.targets); got != tt.any {
+				t.Errorf("ContainsAny(%v, %v) = %v, want %v", tt.data, tt.targets, got, tt.any)
+			}
+		})
+	}
+}
+
 func TestFilter(t *testing.T) {
 	type args struct {
 		data []int
@@ -288,12 +439,43 @@ func TestChunk(t *testing.T) {
 			want: [][]int{
 				{1, 2, 3},
 			},
+		}, {
+			name: "size equals length",
+			args: args{
+				data: []int{1, 2, 3},
+				size: 3,
+			},
+			want: [][]int{
+				{1, 2, 3},
+			},
+		}, {
+			name: "size one",
+			args: args{
+				data: []int{1, 2, 3},
+				size: 1,
+			},
+			want: [][]int{
+				{1}, {2}, {3},
+			},
+		}, {
+			name: "zero size",
+			args: args{
+				data: []int{1, 2, 3},
+				size: 0,
+			},
+			want: [][]int{
+				{1, 2, 3},
+			},
+		}, {
+			name: "empty",
+			args: args{
+				data: []int{},
+				size: 3,
+			},
+			want: [][]int{},
 		},
 	}
 	for _, tt := range tests {
-		if tt.name != "last not full" {
-			continue
-		}
 		t.Run(tt.name, func(t *testing.T) {
 			if got := Chunk(tt.args.data, tt.args.size); !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("Slice() = %v, want %v", got, tt.want)
@@ -301,3 +483,678 @@ func TestChunk(t *testing.T) {
 		})
 	}
 }
+
+func TestChunkFlattenRoundTrip(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for length := 0; length <= 50; length++ {
+		data := make([]int, length)
+		for i := range data {
+			data[i] = rng.Int()
+		}
+		for size := -1; size <= length+2; size++ {
+			chunks := Chunk(data, size)
+			if got := Flatten(chunks); !reflect.DeepEqual(got, data) {
+				t.Fatalf("Flatten(Chunk(len=%d, size=%d)) = %v, want %v", length, size, got, data)
+			}
+			if size <= 0 {
+				continue
+			}
+			// 除最后一个分块外都是满的
+			for i, c := range chunks {
+				if len(c) == 0 || len(c) > size || (i < len(chunks)-1 && len(c) != size) {
+					t.Fatalf("Chunk(len=%d, size=%d) chunk %d has len %d", length, size, i, len(c))
+				}
+			}
+		}
+	}
+}
+
+func TestChunkAppendIsolation(t *testing.T) {
+	data := []int{1, 2, 3, 4}
+	chunks := Chunk(data, 2)
+	_ = append(chunks[0], 100)
+	if !reflect.DeepEqual(data, []int{1, 2, 3, 4}) || !reflect.DeepEqual(chunks[1], []int{3, 4}) {
+		t.Errorf("append to a chunk should not overwrite the next chunk: data=%v chunks=%v", data, chunks)
+	}
+}
+
+func TestFlatten(t *testing.T) {
+	if got := Flatten([][]int{{1, 2}, {}, {3}, nil, {4, 5}}); !reflect.DeepEqual(got, []int{1, 2, 3, 4, 5}) {
+		t.Errorf("Flatten() = %v", got)
+	}
+	if got := Flatten[int](nil); len(got) != 0 {
+		t.Errorf("Flatten(nil) = %v, want empty", got)
+	}
+}
+
+func TestChunkInto(t *testing.T) {
+	data := []int{1, 2, 3, 4, 5, 6, 7}
+	dst := make([][]int, 0, 8)
+
+	got := ChunkInto(data, 3, dst)
+	want := [][]int{{1, 2, 3}, {4, 5, 6}, {7}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("ChunkInto() = %v, want %v", got, want)
+	}
+	if &got[:1][0] != &dst[:1][0] {
+		t.Error("ChunkInto() should reuse dst")
+	}
+
+	// 复用上一次的结果
+	got = ChunkInto(data[:4], 2, got)
+	want = [][]int{{1, 2}, {3, 4}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("ChunkInto() reuse = %v, want %v", got, want)
+	}
+
+	// 对分块 append 不会覆盖下一个分块
+	_ = append(got[0], 100)
+	if data[2] != 3 {
+		t.Errorf("append to a chunk should not overwrite the next chunk: data=%v chunks=%v", data, got)
+	}
+
+	if got = ChunkInto([]int{}, 2, got); len(got) != 0 {
+		t.Errorf("ChunkInto() empty = %v, want empty", got)
+	}
+}
+
+func BenchmarkChunk(b *testing.B) {
+	data := make([]int, 1000)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = Chunk(data, 10)
+	}
+}
+
+func BenchmarkChunkInto(b *testing.B) {
+	data := make([]int, 1000)
+	var dst [][]int
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		dst = ChunkInto(data, 10, dst)
+	}
+}
+
+func TestSum(t *testing.T) {
+	tests := []struct {
+		name string
+		data []int
+		want int
+	}{
+		{
+			name: "空切片",
+			data: []int{},
+			want: 0,
+		}, {
+			name: "单个元素",
+			data: []int{5},
+			want: 5,
+		}, {
+			name: "正负混合",
+			data: []int{-3, 1, 4, -1, 5},
+			want: 6,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Sum(tt.data); got != tt.want {
+				t.Errorf("Sum() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("浮点数", func(t *testing.T) {
+		if got := Sum([]float64{1.5, -0.5, 2}); got != 3 {
+			t.Errorf("Sum() = %v, want %v", got, 3)
+		}
+	})
+}
+
+func TestMinMax(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    []int
+		wantMin int
+		wantMax int
+		wantOk  bool
+	}{
+		{
+			name:   "空切片",
+			data:   []int{},
+			wantOk: false,
+		}, {
+			name:    "单个元素",
+			data:    []int{7},
+			wantMin: 7,
+			wantMax: 7,
+			wantOk:  true,
+		}, {
+			name:    "正负混合",
+			data:    []int{3, -7, 0, 12, -2},
+			wantMin: -7,
+			wantMax: 12,
+			wantOk:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotMin, ok := Min(tt.data)
+			if gotMin != tt.wantMin || ok != tt.wantOk {
+				t.Errorf("Min() = (%v, %v), want (%v, %v)", gotMin, ok, tt.wantMin, tt.wantOk)
+			}
+			gotMax, ok := Max(tt.data)
+			if gotMax != tt.wantMax || ok != tt.wantOk {
+				t.Errorf("Max() = (%v, %v), want (%v, %v)", gotMax, ok, tt.wantMax, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestCount(t *testing.T) {
+	data := []string{"a", "b", "a", "c", "a"}
+	tests := []struct {
+		target string
+		want   int
+	}{
+		{target: "a", want: 3},
+		{target: "b", want: 1},
+		{target: "d", want: 0},
+	}
+	for _, tt := range tests {
+		if got := Count(data, tt.target); got != tt.want {
+			t.Errorf("Count(%q) = %d, want %d", tt.target, got, tt.want)
+		}
+	}
+	if got := Count(nil, "a"); got != 0 {
+		t.Errorf("Count(nil) = %d, want 0", got)
+	}
+}
+
+func TestCountBy(t *testing.T) {
+	isEven := func(n int) bool { return n%2 == 0 }
+	if got := CountBy([]int{1, 2, 3, 4, 6}, isEven); got != 3 {
+		t.Errorf("CountBy() = %d, want 3", got)
+	}
+	if got := CountBy([]int{1, 3}, isEven); got != 0 {
+		t.Errorf("CountBy() = %d, want 0", got)
+	}
+	if got := CountBy(nil, isEven); got != 0 {
+		t.Errorf("CountBy(nil) = %d, want 0", got)
+	}
+}
+
+func TestTakeDrop(t *testing.T) {
+	data := []int{1, 2, 3}
+	tests := []struct {
+		name     string
+		n        int
+		wantTake []int
+		wantDrop []int
+	}{
+		{name: "n小于长度", n: 2, wantTake: []int{1, 2}, wantDrop: []int{3}},
+		{name: "n等于长度", n: 3, wantTake: []int{1, 2, 3}, wantDrop: []int{}},
+		{name: "n大于长度", n: 1000, wantTake: []int{1, 2, 3}, wantDrop: []int{}},
+		{name: "n为0", n: 0, wantTake: []int{}, wantDrop: []int{1, 2, 3}},
+		{name: "n为负数", n: -1, wantTake: []int{}, wantDrop: []int{1, 2, 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Take(data, tt.n); !reflect.DeepEqual(got, tt.wantTake) {
+				t.Errorf("Take() = %v, want %v", got, tt.wantTake)
+			}
+			if got := Drop(data, tt.n); !reflect.DeepEqual(got, tt.wantDrop) {
+				t.Errorf("Drop() = %v, want %v", got, tt.wantDrop)
+			}
+		})
+	}
+}
+
+func TestTakeWhileDropWhile(t *testing.T) {
+	lessThan3 := func(n int) bool { return n < 3 }
+	tests := []struct {
+		name     string
+		data     []int
+		f        func(int) bool
+		wantTake []int
+		wantDrop []int
+	}{
+		{
+			name:     "前缀满足",
+			data:     []int{1, 2, 3, 1, 2},
+			f:        lessThan3,
+			wantTake: []int{1, 2},
+			wantDrop: []int{3, 1, 2},
+		}, {
+			name:     "从不满足",
+			data:     []int{5, 1, 2},
+			f:        lessThan3,
+			wantTake: []int{},
+			wantDrop: []int{5, 1, 2},
+		}, {
+			name:     "始终满足",
+			data:     []int{1, 2, 1},
+			f:        lessThan3,
+			wantTake: []int{1, 2, 1},
+			wantDrop: []int{},
+		}, {
+			name:     "空切片",
+			data:     []int{},
+			f:        lessThan3,
+			wantTake: []int{},
+			wantDrop: []int{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TakeWhile(tt.data, tt.f); !reflect.DeepEqual(got, tt.wantTake) {
+				t.Errorf("TakeWhile() = %v, want %v", got, tt.wantTake)
+			}
+			if got := DropWhile(tt.data, tt.f); !reflect.DeepEqual(got, tt.wantDrop) {
+				t.Errorf("DropWhile() = %v, want %v", got, tt.wantDrop)
+			}
+		})
+	}
+}
+
+func TestInsert(t *testing.T) {
+	tests := []struct {
+		name   string
+		data   []int
+		index  int
+		values []int
+		want   []int
+	}{
+		{name: "插入开头", data: []int{1, 2, 3}, index: 0, values: []int{0}, want: []int{0, 1, 2, 3}},
+		{name: "插入中间", data: []int{1, 2, 3}, index: 1, values: []int{8, 9}, want: []int{1, 8, 9, 2, 3}},
+		{name: "插入末尾", data: []int{1, 2, 3}, index: 3, values: []int{4}, want: []int{1, 2, 3, 4}},
+		{name: "空切片", data: nil, index: 0, values: []int{1}, want: []int{1}},
+		{name: "index为负数", data: []int{1, 2}, index: -1, values: []int{0}, want: []int{1, 2}},
+		{name: "index越界", data: []int{1, 2}, index: 3, values: []int{0}, want: []int{1, 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			origin := append([]int(nil), tt.data...)
+			if got := Insert(tt.data, tt.index, tt.values...); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Insert() = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(tt.data, origin) {
+				t.Errorf("Insert() 不应修改原切片, got %v, want %v", tt.data, origin)
+			}
+		})
+	}
+}
+
+func TestRemoveAt(t *testing.T) {
+	tests := []struct {
+		name  string
+		data  []int
+		index int
+		want  []int
+	}{
+		{name: "删除开头", data: []int{1, 2, 3}, index: 0, want: []int{2, 3}},
+		{name: "删除中间", data: []int{1, 2, 3}, index: 1, want: []int{1, 3}},
+		{name: "删除末尾", data: []int{1, 2, 3}, index: 2, want: []int{1, 2}},
+		{name: "删除唯一元素", data: []int{1}, index: 0, want: []int{}},
+		{name: "index为负数", data: []int{1, 2}, index: -1, want: []int{1, 2}},
+		{name: "index越界", data: []int{1, 2}, index: 2, want: []int{1, 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			origin := append([]int(nil), tt.data...)
+			if got := RemoveAt(tt.data, tt.index); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("RemoveAt() = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(tt.data, origin) {
+				t.Errorf("RemoveAt() 不应修改原切片, got %v, want %v", tt.data, origin)
+			}
+		})
+	}
+}
+
+func TestFlattenDeep(t *testing.T) {
+	// [1, [2, [3, [4]]], "a"]
+	data := []interface{}{1, []interface{}{2, []interface{}{3, []interface{}{4}}}, "a"}
+	tests := []struct {
+		name  string
+		depth int
+		want  []interface{}
+	}{
+		{name: "不展开", depth: 0, want: []interface{}{1, []interface{}{2, []interface{}{3, []interface{}{4}}}, "a"}},
+		{name: "展开1层", depth: 1, want: []interface{}{1, 2, []interface{}{3, []interface{}{4}}, "a"}},
+		{name: "展开2层", depth: 2, want: []interface{}{1, 2, 3, []interface{}{4}, "a"}},
+		{name: "完全展开", depth: -1, want: []interface{}{1, 2, 3, 4, "a"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FlattenDeep(data, tt.depth); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FlattenDeep() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if got := FlattenDeep(nil, -1); len(got) != 0 {
+		t.Errorf("FlattenDeep(nil) = %v, want empty", got)
+	}
+}
+
+func TestZip(t *testing.T) {
+	tests := []struct {
+		name string
+		a    []string
+		b    []int
+		want []Pair[string, int]
+	}{
+		{
+			name: "等长",
+			a:    []string{"a", "b", "c"},
+			b:    []int{1, 2, 3},
+			want: []Pair[string, int]{{"a", 1}, {"b", 2}, {"c", 3}},
+		}, {
+			name: "不等长以较短为准",
+			a:    []string{"a", "b", "c"},
+			b:    []int{1},
+			want: []Pair[string, int]{{"a", 1}},
+		}, {
+			name: "空切片",
+			a:    nil,
+			b:    []int{1, 2},
+			want: []Pair[string, int]{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Zip(tt.a, tt.b); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Zip() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCartesianProduct(t *testing.T) {
+	tests := []struct {
+		name string
+		a    []string
+		b    []int
+		want []Pair[string, int]
+	}{
+		{
+			name: "正常组合",
+			a:    []string{"a", "b"},
+			b:    []int{1, 2, 3},
+			want: []Pair[string, int]{{"a", 1}, {"a", 2}, {"a", 3}, {"b", 1}, {"b", 2}, {"b", 3}},
+		}, {
+			name: "单个元素",
+			a:    []string{"a"},
+			b:    []int{1},
+			want: []Pair[string, int]{{"a", 1}},
+		}, {
+			name: "a为空",
+			a:    nil,
+			b:    []int{1, 2},
+			want: []Pair[string, int]{},
+		}, {
+			name: "b为空",
+			a:    []string{"a", "b"},
+			b:    []int{},
+			want: []Pair[string, int]{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CartesianProduct(tt.a, tt.b)
+			if len(got) != len(tt.a)*len(tt.b) {
+				t.Errorf("len(CartesianProduct()) = %d, want %d", len(got), len(tt.a)*len(tt.b))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CartesianProduct() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnzip(t *testing.T) {
+	tests := []struct {
+		name  string
+		pairs []Pair[string, int]
+		wantA []string
+		wantB []int
+	}{
+		{
+			name:  "正常拆分",
+			pairs: []Pair[string, int]{{"a", 1}, {"b", 2}},
+			wantA: []string{"a", "b"},
+			wantB: []int{1, 2},
+		}, {
+			name:  "空切片",
+			pairs: nil,
+			wantA: []string{},
+			wantB: []int{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotA, gotB := Unzip(tt.pairs)
+			if !reflect.DeepEqual(gotA, tt.wantA) || !reflect.DeepEqual(gotB, tt.wantB) {
+				t.Errorf("Unzip() = (%v, %v), want (%v, %v)", gotA, gotB, tt.wantA, tt.wantB)
+			}
+		})
+	}
+}
+
+func TestShuffle(t *testing.T) {
+	data := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+
+	a := append([]int(nil), data...)
+	b := append([]int(nil), data...)
+	Shuffle(a, rand.New(rand.NewSource(42)))
+	Shuffle(b, rand.New(rand.NewSource(42)))
+	if !reflect.DeepEqual(a, b) {
+		t.Errorf("Shuffle with the same seed = %v and %v, want equal", a, b)
+	}
+	if reflect.DeepEqual(a, data) {
+		t.Errorf("Shuffle() = %v, want a different order", a)
+	}
+	sorted := append([]int(nil), a...)
+	sort.Ints(sorted)
+	if !reflect.DeepEqual(sorted, data) {
+		t.Errorf("Shuffle() = %v, want a permutation of %v", a, data)
+	}
+
+	// 不传 rng 时使用全局生成器，空切片与单元素不 panic
+	Shuffle([]int{})
+	Shuffle([]int{1})
+}
+
+func TestSample(t *testing.T) {
+	data := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	orig := append([]int(nil), data...)
+
+	a := Sample(data, 3, rand.New(rand.NewSource(7)))
+	b := Sample(data, 3, rand.New(rand.NewSource(7)))
+	if !reflect.DeepEqual(a, b) {
+		t.Errorf("Sample with the same seed = %v and %v, want equal", a, b)
+	}
+	if len(a) != 3 {
+		t.Fatalf("Sample() len = %d, want 3", len(a))
+	}
+	seen := make(map[int]bool)
+	for _, v := range a {
+		if seen[v] || !InArray(v, data) {
+			t.Errorf("Sample() = %v, want distinct elements of data", a)
+		}
+		seen[v] = true
+	}
+	if !reflect.DeepEqual(data, orig) {
+		t.Errorf("Sample modified data: %v", data)
+	}
+
+	if got := Sample(data, 100); len(got) != len(data) {
+		t.Errorf("Sample(n > len) len = %d, want %d", len(got), len(data))
+	}
+	if got := Sample(data, -1); len(got) != 0 {
+		t.Errorf("Sample(n < 0) = %v, want empty", got)
+	}
+}
+
+func TestSampleEvery(t *testing.T) {
+	data := []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
+	tests := []struct {
+		name string
+		data []int
+		n    int
+		want []int
+	}{
+		{"n 为 1 返回全部", data, 1, data},
+		{"常规步长", data, 3, []int{0, 3, 6, 9}},
+		{"步长整除长度", data, 5, []int{0, 5}},
+		{"n 超过长度只取第一个", data, 20, []int{0}},
+		{"n 小于 1 视为 1", data, 0, data},
+		{"空切片", nil, 2, []int{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SampleEvery(tt.data, tt.n); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SampleEvery(%d) = %v, want %v", tt.n, got, tt.want)
+			}
+		})
+	}
+
+	// 返回新切片，修改结果不影响 data
+	got := SampleEvery(data, 1)
+	got[0] = 100
+	if data[0] != 0 {
+		t.Error("SampleEvery should not share the backing array with data")
+	}
+}
+
+func TestAnySlice(t *testing.T) {
+	data := []int{1, 2, 3}
+
+	anys := ToAnySlice(data)
+	if want := []interface{}{1, 2, 3}; !reflect.DeepEqual(anys, want) {
+		t.Errorf("ToAnySlice() = %v, want %v", anys, want)
+	}
+	if got := fmt.Sprintf("%d-%d-%d", anys...); got != "1-2-3" {
+		t.Errorf("Sprintf with ToAnySlice() = %q", got)
+	}
+
+	back, err := FromAnySlice[int](anys)
+	if err != nil || !reflect.DeepEqual(back, data) {
+		t.Errorf("FromAnySlice() = (%v, %v), want (%v, nil)", back, err, data)
+	}
+
+	if got := ToAnySlice([]string(nil)); len(got) != 0 {
+		t.Errorf("ToAnySlice(nil) = %v, want empty", got)
+	}
+
+	// 元素类型不一致
+	got, err := FromAnySlice[int]([]interface{}{1, "2", 3})
+	if !errors.Is(err, ErrTypeMismatch) || got != nil {
+		t.Errorf("FromAnySlice(mismatch) = (%v, %v), want (nil, ErrTypeMismatch)", got, err)
+	}
+	if err != nil && !strings.Contains(err.Error(), "element 1 is string, want int") {
+		t.Errorf("error = %q, want the mismatched index and types", err)
+	}
+
+	// 目标为接口类型时按接口断言
+	errs, err := FromAnySlice[error]([]interface{}{errors.New("a"), fmt.Errorf("b")})
+	if err != nil || len(errs) != 2 {
+		t.Errorf("FromAnySlice[error]() = (%v, %v)", errs, err)
+	}
+}
+
+func TestSortBy(t *testing.T) {
+	type user struct {
+		Name string
+		Age  int
+	}
+	users := []user{{"carol", 35}, {"alice", 18}, {"bob", 27}, {"dave", 5}}
+
+	SortBy(users, func(u user) int { return u.Age })
+	if got := MapColumn(users, func(u user) string { return u.Name }); !reflect.DeepEqual(got, []string{"dave", "alice", "bob", "carol"}) {
+		t.Errorf("SortBy(age) = %v", got)
+	}
+
+	SortBy(users, func(u user) string { return u.Name })
+	if got := MapColumn(users, func(u user) string { return u.Name }); !reflect.DeepEqual(got, []string{"alice", "bob", "carol", "dave"}) {
+		t.Errorf("SortBy(name) = %v", got)
+	}
+}
+
+func TestSortStableBy(t *testing.T) {
+	type user struct {
+		Name string
+		Age  int
+	}
+	users := []user{{"a", 30}, {"b", 20}, {"c", 30}, {"d", 20}, {"e", 10}, {"f", 30}}
+
+	SortStableBy(users, func(u user) int { return u.Age })
+	// 年龄相同的用户保持原有顺序
+	want := []string{"e", "b", "d", "a", "c", "f"}
+	if got := MapColumn(users, func(u user) string { return u.Name }); !reflect.DeepEqual(got, want) {
+		t.Errorf("SortStableBy(age) = %v, want %v", got, want)
+	}
+}
+
+func TestChunkMap(t *testing.T) {
+	tests := []struct {
+		name       string
+		data       []int
+		size       int
+		wantSums   []int
+		wantChunks [][]int
+	}{
+		{"整除", []int{1, 2, 3, 4, 5, 6}, 2, []int{3, 7, 11}, [][]int{{1, 2}, {3, 4}, {5, 6}}},
+		{"不整除", []int{1, 2, 3, 4, 5, 6, 7}, 3, []int{6, 15, 7}, [][]int{{1, 2, 3}, {4, 5, 6}, {7}}},
+		{"size 大于长度", []int{1, 2}, 5, []int{3}, [][]int{{1, 2}}},
+		{"size <= 0", []int{1, 2, 3}, 0, []int{6}, [][]int{{1, 2, 3}}},
+		{"空切片", nil, 2, []int{}, [][]int{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ChunkMap(tt.data, tt.size, Sum[int]); !reflect.DeepEqual(got, tt.wantSums) {
+				t.Errorf("ChunkMap(Sum) = %v, want %v", got, tt.wantSums)
+			}
+			chunks := ChunkMap(tt.data, tt.size, func(c []int) []int { return append([]int{}, c...) })
+			if !reflect.DeepEqual(chunks, tt.wantChunks) {
+				t.Errorf("ChunkMap chunk boundaries = %v, want %v", chunks, tt.wantChunks)
+			}
+		})
+	}
+}
+
+func TestForEachIndexed(t *testing.T) {
+	var got []string
+	err := ForEachIndexed([]string{"a", "b", "c"}, func(idx int, item string) error {
+		got = append(got, strconv.Itoa(idx)+item)
+		return nil
+	})
+	if err != nil || !reflect.DeepEqual(got, []string{"0a", "1b", "2c"}) {
+		t.Errorf("ForEachIndexed() = %v, %v, want [0a 1b 2c], nil", got, err)
+	}
+
+	errStop := errors.New("stop")
+	got = nil
+	err = ForEachIndexed([]string{"a", "b", "c"}, func(idx int, item string) error {
+		if idx == 1 {
+			return errStop
+		}
+		got = append(got, strconv.Itoa(idx)+item)
+		return nil
+	})
+	if !errors.Is(err, errStop) || !reflect.DeepEqual(got, []string{"0a"}) {
+		t.Errorf("ForEachIndexed() = %v, %v, want [0a], errStop", got, err)
+	}
+}
+
+func TestMapIndexed(t *testing.T) {
+	got := MapIndexed([]string{"a", "b", "c"}, func(idx int, item string) string {
+		return strconv.Itoa(idx) + item
+	})
+	if want := []string{"0a", "1b", "2c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("MapIndexed() = %v, want %v", got, want)
+	}
+	if got := MapIndexed(nil, func(int, int) int { return 0 }); len(got) != 0 {
+		t.Errorf("MapIndexed(nil) = %v, want empty", got)
+	}
+}
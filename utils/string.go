@@ -0,0 +1,25 @@
+package utils
+
+import "unicode/utf8"
+
+// ChunkString 按 rune 数将字符串切分为多段，每段最多 size 个 rune，不会拆开多字节字符
+// s 为空时返回空切片，size <= 0 时将 s 整体作为一段
+func ChunkString(s string, size int) []string {
+	if s == "" {
+		return []string{}
+	}
+	if size <= 0 {
+		return []string{s}
+	}
+
+	result := make([]string, 0, utf8.RuneCountInString(s)/size+1)
+	start, count := 0, 0
+	for i := range s {
+		if count == size {
+			result = append(result, s[start:i])
+			start, count = i, 0
+		}
+		count++
+	}
+	return append(result, s[start:])
+}
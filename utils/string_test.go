@@ -0,0 +1,70 @@
+package utils
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestChunkString(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		size int
+		want []string
+	}{
+		{
+			name: "ASCII",
+			s:    "abcdefg",
+			size: 3,
+			want: []string{"abc", "def", "g"},
+		}, {
+			name: "刚好整除",
+			s:    "abcdef",
+			size: 3,
+			want: []string{"abc", "def"},
+		}, {
+			name: "中文",
+			s:    "你好世界，再见",
+			size: 2,
+			want: []string{"你好", "世界", "，再", "见"},
+		}, {
+			name: "emoji混合",
+			s:    "a😀b😁c",
+			size: 2,
+			want: []string{"a😀", "b😁", "c"},
+		}, {
+			name: "size大于长度",
+			s:    "你好",
+			size: 10,
+			want: []string{"你好"},
+		}, {
+			name: "size为0",
+			s:    "abc",
+			size: 0,
+			want: []string{"abc"},
+		}, {
+			name: "空字符串",
+			s:    "",
+			size: 3,
+			want: []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ChunkString(tt.s, tt.size)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ChunkString() = %q, want %q", got, tt.want)
+			}
+			for _, chunk := range got {
+				if !utf8.ValidString(chunk) {
+					t.Errorf("chunk %q splits a rune", chunk)
+				}
+			}
+			if strings.Join(got, "") != tt.s {
+				t.Errorf("chunks joined = %q, want %q", strings.Join(got, ""), tt.s)
+			}
+		})
+	}
+}
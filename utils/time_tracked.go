@@ -0,0 +1,14 @@
+package utils
+
+import "time"
+
+// TimeTracked 执行 fn 并统计耗时，耗时超过 threshold 时调用 onSlow，返回 fn 的错误
+// 可用于任意需要慢操作告警的场景，fn 返回错误时同样会统计耗时
+func TimeTracked(threshold time.Duration, onSlow func(elapsed time.Duration), fn func() error) error {
+	begin := time.Now()
+	err := fn()
+	if elapsed := time.Since(begin); elapsed > threshold && onSlow != nil {
+		onSlow(elapsed)
+	}
+	return err
+}
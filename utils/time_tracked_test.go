@@ -0,0 +1,37 @@
+package utils
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTimeTracked(t *testing.T) {
+	t.Run("快速操作不触发", func(t *testing.T) {
+		called := false
+		err := TimeTracked(50*time.Millisecond, func(time.Duration) { called = true }, func() error {
+			return nil
+		})
+		if err != nil {
+			t.Errorf("TimeTracked() error = %v, want nil", err)
+		}
+		if called {
+			t.Error("快速操作不应触发 onSlow")
+		}
+	})
+
+	t.Run("慢操作触发并返回错误", func(t *testing.T) {
+		wantErr := errors.New("slow")
+		var got time.Duration
+		err := TimeTracked(10*time.Millisecond, func(elapsed time.Duration) { got = elapsed }, func() error {
+			time.Sleep(30 * time.Millisecond)
+			return wantErr
+		})
+		if !errors.Is(err, wantErr) {
+			t.Errorf("TimeTracked() error = %v, want %v", err, wantErr)
+		}
+		if got < 30*time.Millisecond || got > 200*time.Millisecond {
+			t.Errorf("onSlow elapsed = %v, want about 30ms", got)
+		}
+	})
+}
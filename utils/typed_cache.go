@@ -0,0 +1,70 @@
+package utils
+
+import "time"
+
+// TypedCache 带类型的本地缓存，包装 LocalCache，调用方无需再做类型断言
+// 存储、过期与 singleflight 行为均复用底层的 LocalCache
+type TypedCache[V any] struct {
+	lc *LocalCache
+}
+
+// NewTypedCache 包装已有的 LocalCache
+func NewTypedCache[V any](lc *LocalCache) *TypedCache[V] {
+	return &TypedCache[V]{lc: lc}
+}
+
+// Get 从缓存获取数据，若缓存中的值不是 V 类型（如通过底层 LocalCache 写入了其他类型），视为不存在
+func (tc *TypedCache[V]) Get(key string) (V, bool) {
+	data, exists := tc.lc.Get(key)
+	if !exists {
+		var zero V
+		return zero, false
+	}
+	v, ok := data.(V)
+	return v, ok
+}
+
+// Set 设置缓存数据
+func (tc *TypedCache[V]) Set(key string, data V) {
+	tc.lc.Set(key, data)
+}
+
+// Delete 删除缓存数据
+func (tc *TypedCache[V]) Delete(key string) {
+	tc.lc.Delete(key)
+}
+
+// GetOrSet 从缓存获取数据，如果不存在则执行函数获取并设置缓存
+// 与 Get 一致，缓存中的值不是 V 类型时视为不存在，执行函数重新获取并覆盖
+func (tc *TypedCache[V]) GetOrSet(key string, fn func() (V, error)) (V, bool, error) {
+	data, fromCache, err := tc.lc.GetOrSet(key, func() (interface{}, error) {
+		return fn()
+	})
+	if err != nil {
+		var zero V
+		return zero, fromCache, err
+	}
+	if v, ok := data.(V); ok {
+		return v, fromCache, nil
+	}
+
+	v, err := fn()
+	if err != nil {
+		var zero V
+		return zero, false, err
+	}
+	tc.lc.Set(key, v)
+	return v, false, nil
+}
+
+// AddHotKey 将 key 设为热点 key，在缓存项过期前 ahead 时间后台刷新，见 LocalCache.AddHotKey
+func (tc *TypedCache[V]) AddHotKey(key string, ahead time.Duration, fn func() (V, error)) error {
+	return tc.lc.AddHotKey(key, ahead, func() (interface{}, error) {
+		return fn()
+	})
+}
+
+// RemoveHotKey 停止 key 的后台预刷新
+func (tc *TypedCache[V]) RemoveHotKey(key string) {
+	tc.lc.RemoveHotKey(key)
+}
@@ -0,0 +1,101 @@
+package utils
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTypedCache(t *testing.T) {
+	type User struct {
+		ID   int
+		Name string
+	}
+
+	t.Run("设置和获取结构体", func(t *testing.T) {
+		cache := NewTypedCache[User](NewLocalCache(time.Hour))
+		cache.Set("user:1", User{ID: 1, Name: "Alice"})
+
+		user, ok := cache.Get("user:1")
+		if !ok || user.Name != "Alice" {
+			t.Errorf("Get() = (%v, %v)", user, ok)
+		}
+
+		if _, ok := cache.Get("user:2"); ok {
+			t.Error("user:2 不应存在")
+		}
+	})
+
+	t.Run("GetOrSet", func(t *testing.T) {
+		cache := NewTypedCache[User](NewLocalCache(time.Hour))
+		calls := 0
+		load := func() (User, error) {
+			calls++
+			return User{ID: 2, Name: "Bob"}, nil
+		}
+
+		user, fromCache, err := cache.GetOrSet("user:2", load)
+		if err != nil || fromCache || user.Name != "Bob" {
+			t.Fatalf("首次 GetOrSet() = (%v, %v, %v)", user, fromCache, err)
+		}
+		user, fromCache, err = cache.GetOrSet("user:2", load)
+		if err != nil || !fromCache || user.ID != 2 {
+			t.Fatalf("再次 GetOrSet() = (%v, %v, %v)", user, fromCache, err)
+		}
+		if calls != 1 {
+			t.Errorf("函数调用次数应为 1，实际为 %d", calls)
+		}
+	})
+
+	t.Run("GetOrSet 出错", func(t *testing.T) {
+		cache := NewTypedCache[User](NewLocalCache(time.Hour))
+		errLoad := errors.New("load error")
+
+		user, _, err := cache.GetOrSet("user:3", func() (User, error) {
+			return User{ID: 3}, errLoad
+		})
+		if !errors.Is(err, errLoad) || user != (User{}) {
+			t.Errorf("GetOrSet() = (%v, %v)，应返回零值和错误", user, err)
+		}
+	})
+
+	t.Run("底层缓存中类型不匹配视为不存在", func(t *testing.T) {
+		lc := NewLocalCache(time.Hour)
+		lc.Set("user:4", "not a user")
+		cache := NewTypedCache[User](lc)
+
+		if _, ok := cache.Get("user:4"); ok {
+			t.Error("类型不匹配时应返回 false")
+		}
+
+		// GetOrSet 同样视为不存在，重新加载并覆盖
+		user, fromCache, err := cache.GetOrSet("user:4", func() (User, error) {
+			return User{ID: 4, Name: "dave"}, nil
+		})
+		if err != nil || fromCache || user.Name != "dave" {
+			t.Errorf("GetOrSet() = (%+v, %v, %v), want reloaded user", user, fromCache, err)
+		}
+		if got, ok := cache.Get("user:4"); !ok || got.Name != "dave" {
+			t.Errorf("Get() after GetOrSet = (%+v, %v), want reloaded user", got, ok)
+		}
+	})
+}
+
+func TestTypedCache_AddHotKey(t *testing.T) {
+	cache := NewTypedCache[int](NewLocalCache(30 * time.Millisecond))
+	defer cache.RemoveHotKey("hot")
+
+	calls := 0
+	if err := cache.AddHotKey("hot", 10*time.Millisecond, func() (int, error) {
+		calls++
+		return calls, nil
+	}); err != nil {
+		t.Fatalf("AddHotKey() error = %v", err)
+	}
+
+	time.Sleep(70 * time.Millisecond)
+	v, ok := cache.Get("hot")
+	if !ok || v < 2 {
+		t.Errorf("Get() = (%v, %v)，应已在后台刷新", v, ok)
+	}
+}